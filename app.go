@@ -1,3 +1,246 @@
 package cli
 
-type App struct{}
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type App struct {
+	Name                    string
+	Usage                   string
+	UsageText               string
+	ArgsUsage               string
+	Description             string
+	Version                 string
+	Flags                   []Flag
+	Commands                Commands
+	CommandNotFound         CommandNotFoundFunc
+	OnUsageError            OnUsageErrorFunc
+	Before                  BeforeFunc
+	Action                  ActionFunc
+	After                   AfterFunc
+	ExitErrHandler          ExitErrHandleFunc
+	HideHelp                bool
+	HideVersion             bool
+	EnableBashCompletion    bool
+	EnableCompletionCommand bool
+	BashComplete            BashCompleteFunc
+	Writer                  io.Writer
+	categories              CommandCategories
+	didSetup                bool
+}
+
+func NewApp() *App {
+	return &App{
+		Name:   filepath.Base(os.Args[0]),
+		Writer: os.Stdout,
+	}
+}
+
+func (a *App) Setup() {
+	if a.didSetup {
+		return
+	}
+	a.didSetup = true
+	if a.Name == "" {
+		a.Name = filepath.Base(os.Args[0])
+	}
+	if a.Writer == nil {
+		a.Writer = os.Stdout
+	}
+	if a.EnableCompletionCommand {
+		a.EnableBashCompletion = true
+		if a.Command("completion") == nil {
+			a.Commands = append(a.Commands, newCompletionCommand())
+		}
+	}
+	if !a.HideHelp {
+		if a.Command(helpCommand.Name) == nil {
+			a.Commands = append(a.Commands, helpCommand)
+		}
+		a.Flags = append(a.Flags, HelpFlag)
+	}
+	if !a.HideVersion {
+		if a.Version == "" {
+			a.Version = a.BuildInfo().Version
+		}
+		a.Flags = append(a.Flags, VersionFlag)
+	}
+	if !hasFlagName(a.Flags, CLISpecFlag.GetName()) {
+		a.Flags = append(a.Flags, CLISpecFlag)
+	}
+	if a.EnableBashCompletion && a.Command(completeCommand.Name) == nil {
+		a.Commands = append(a.Commands, completeCommand)
+	}
+	a.categories = CommandCategories{}
+	for _, command := range a.Commands {
+		a.categories = a.categories.AddCommand(command.Category, command)
+	}
+	sort.Sort(a.categories)
+}
+
+func (a *App) Run(arguments []string) (err error) {
+	a.Setup()
+
+	set, err := flagSet(a.Name, a.Flags)
+	if err != nil {
+		return err
+	}
+	if len(arguments) > 0 {
+		arguments = arguments[1:]
+	}
+	shellComplete, arguments, completeWord := checkShellCompleteFlag(a, arguments)
+	arguments = versionJSONArguments(a, arguments)
+	err = set.Parse(arguments)
+	if err == nil {
+		err = normalizeFlags(a.Flags, set)
+	}
+	context := NewContext(a, set, nil)
+	context.shellComplete = shellComplete
+	context.completeWord = completeWord
+	if err != nil {
+		if shellComplete {
+			ShowCompletions(context)
+			return nil
+		}
+		return a.handleUsageError(context, a.OnUsageError, err, false)
+	}
+
+	if checkCompletions(context, a.Commands) {
+		return nil
+	}
+
+	if !a.HideHelp && checkHelp(context) {
+		return ShowAppHelp(context)
+	}
+
+	if !a.HideVersion && checkVersion(context) {
+		if versionFormat(context) == "json" {
+			return printVersionJSON(context)
+		}
+		ShowVersion(context)
+		return nil
+	}
+
+	if context.Bool(CLISpecFlag.GetName()) {
+		return printSpec(context)
+	}
+
+	if a.EnableBashCompletion && context.Args().First() == completeCommand.Name {
+		if c := a.Command(completeCommand.Name); c != nil {
+			return c.Run(context)
+		}
+	}
+
+	if !shellComplete && (a.HideHelp || !helpRequested(context, a.Commands)) {
+		if err = checkRequiredFlags(a.Flags, context); err != nil {
+			a.handleExitCoder(context, err)
+			return err
+		}
+	}
+
+	if a.After != nil {
+		defer func() {
+			if afterErr := a.After(context); afterErr != nil {
+				a.handleExitCoder(context, afterErr)
+				if err != nil {
+					err = NewMultiError(err, afterErr)
+				} else {
+					err = afterErr
+				}
+			}
+		}()
+	}
+
+	if a.Before != nil {
+		if beforeErr := a.Before(context); beforeErr != nil {
+			a.handleExitCoder(context, beforeErr)
+			err = beforeErr
+			return err
+		}
+	}
+
+	if name := context.Args().First(); name != "" {
+		if c := a.Command(name); c != nil {
+			return c.Run(context)
+		}
+		if a.Action == nil && len(a.Commands) > 0 {
+			return a.commandNotFound(context, a.Commands, name)
+		}
+	}
+
+	if a.Action == nil {
+		return ShowAppHelp(context)
+	}
+	err = a.Action(context)
+	a.handleExitCoder(context, err)
+	return err
+}
+
+func (a *App) Command(name string) *Command {
+	return a.Commands.Command(name)
+}
+
+func (a *App) Categories() CommandCategories {
+	return a.categories
+}
+
+func (a *App) VisibleCategories() []*CommandCategory {
+	return visibleCategories(a.categories)
+}
+
+func (a *App) VisibleCommands() []Command {
+	return visibleCommands(a.Commands)
+}
+
+func (a *App) VisibleFlags() []Flag {
+	return visibleFlags(a.Flags)
+}
+
+func (a *App) commandNotFound(context *Context, commands Commands, name string) error {
+	if a.CommandNotFound != nil {
+		a.CommandNotFound(context, name)
+		return nil
+	}
+	err := NewExitError(commandNotFoundMessage(context.CommandPath(), name, SuggestCommands(commands, name)), 3)
+	a.handleExitCoder(context, err)
+	return err
+}
+
+func (a *App) handleUsageError(context *Context, onUsageError OnUsageErrorFunc, err error, isSubcommand bool) error {
+	path := context.CommandPath()
+	usageErr := newUsageError(path, err)
+	if onUsageError != nil {
+		err = onUsageError(context, usageErr, isSubcommand)
+		a.handleExitCoder(context, err)
+		return err
+	}
+	fmt.Fprintf(ErrWriter, "Incorrect Usage: %s\n", usageErr)
+	fmt.Fprintf(ErrWriter, "Usage: %s [options] [arguments...]\n", path)
+	if isSubcommand {
+		fmt.Fprintf(ErrWriter, "Run '%s help %s' for usage.\n", a.Name, strings.TrimPrefix(path, a.Name+" "))
+	} else {
+		fmt.Fprintf(ErrWriter, "Run '%s help' for usage.\n", a.Name)
+	}
+	if a.ExitErrHandler != nil {
+		a.ExitErrHandler(context, usageErr)
+	} else {
+		HandleExitCoder(NewExitError("", usageErr.ExitCode()))
+	}
+	return usageErr
+}
+
+func (a *App) handleExitCoder(context *Context, err error) {
+	if err == nil {
+		return
+	}
+	if a.ExitErrHandler != nil {
+		a.ExitErrHandler(context, err)
+	} else {
+		HandleExitCoder(err)
+	}
+}
@@ -0,0 +1,338 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+func TestAppRunLifecycle(t *testing.T) {
+	var calls []string
+	app := &App{
+		Name:  "tool",
+		Flags: []Flag{StringFlag{Name: "name, n"}},
+		Before: func(c *Context) error {
+			calls = append(calls, "before")
+			return nil
+		},
+		Action: func(c *Context) error {
+			calls = append(calls, "action:"+c.String("name"))
+			return nil
+		},
+		After: func(c *Context) error {
+			calls = append(calls, "after")
+			return nil
+		},
+	}
+	if err := app.Run([]string{"tool", "-n", "gopher"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"before", "action:gopher", "after"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected calls %v, got %v", expected, calls)
+	}
+}
+
+func TestAppRunBeforeErrorSkipsAction(t *testing.T) {
+	var handled error
+	actionRan, afterRan := false, false
+	app := &App{
+		Before: func(c *Context) error { return errors.New("nope") },
+		Action: func(c *Context) error {
+			actionRan = true
+			return nil
+		},
+		After: func(c *Context) error {
+			afterRan = true
+			return nil
+		},
+		ExitErrHandler: func(c *Context, err error) { handled = err },
+	}
+	err := app.Run([]string{"tool"})
+	if err == nil || err.Error() != "nope" {
+		t.Fatalf("expected before error, got %v", err)
+	}
+	if actionRan {
+		t.Error("expected action not to run")
+	}
+	if !afterRan {
+		t.Error("expected after to run")
+	}
+	if handled != err {
+		t.Errorf("expected ExitErrHandler to receive %v, got %v", err, handled)
+	}
+}
+
+func TestAppRunNestedSubcommands(t *testing.T) {
+	var verbose bool
+	var remote, name string
+	app := &App{
+		Name:  "tool",
+		Flags: []Flag{BoolFlag{Name: "verbose"}},
+		Commands: []Command{
+			{
+				Name:  "remote",
+				Flags: []Flag{StringFlag{Name: "remote-name", Value: "origin"}},
+				Subcommands: []Command{
+					{
+						Name:    "add",
+						Aliases: []string{"a"},
+						Flags:   []Flag{StringFlag{Name: "name"}},
+						Action: func(c *Context) error {
+							verbose = c.GlobalBool("verbose")
+							remote = c.GlobalString("remote-name")
+							name = c.String("name")
+							return nil
+						},
+					},
+				},
+			},
+		},
+	}
+	err := app.Run([]string{"tool", "--verbose", "remote", "--remote-name", "upstream", "a", "--name", "x"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !verbose || remote != "upstream" || name != "x" {
+		t.Errorf("unexpected values verbose=%v remote=%q name=%q", verbose, remote, name)
+	}
+}
+
+func TestAppRunCommandNotFoundSuggests(t *testing.T) {
+	var handled error
+	app := &App{
+		Name: "tool",
+		Commands: []Command{
+			{Name: "status", Action: func(c *Context) error { return nil }},
+			{Name: "stash", Action: func(c *Context) error { return nil }},
+			{Name: "secret", Hidden: true},
+		},
+		ExitErrHandler: func(c *Context, err error) { handled = err },
+	}
+	err := app.Run([]string{"tool", "stauts"})
+	expected := "'stauts' is not a tool command. did you mean 'status'?"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("expected %q, got %v", expected, err)
+	}
+	if exitErr, ok := handled.(ExitCoder); !ok || exitErr.ExitCode() == 0 {
+		t.Errorf("expected non-zero ExitCoder, got %#v", handled)
+	}
+}
+
+func TestAppRunCommandNotFoundHook(t *testing.T) {
+	var missing string
+	var suggestions []string
+	app := &App{
+		Commands: []Command{{Name: "status"}},
+		CommandNotFound: func(c *Context, name string) {
+			missing = name
+			suggestions = SuggestCommands(c.App.Commands, name)
+		},
+	}
+	if err := app.Run([]string{"tool", "statu"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if missing != "statu" || !reflect.DeepEqual(suggestions, []string{"status"}) {
+		t.Errorf("unexpected hook call %q %v", missing, suggestions)
+	}
+}
+
+func TestAppRunUsageError(t *testing.T) {
+	var got *UsageError
+	var gotSubcommand bool
+	app := &App{
+		Name: "tool",
+		Commands: []Command{
+			{
+				Name:   "serve",
+				Flags:  []Flag{IntFlag{Name: "port"}},
+				Action: func(c *Context) error { return nil },
+			},
+		},
+		OnUsageError: func(c *Context, err error, isSubcommand bool) error {
+			got, _ = err.(*UsageError)
+			gotSubcommand = isSubcommand
+			return nil
+		},
+	}
+	if err := app.Run([]string{"tool", "serve", "--port", "http"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got == nil {
+		t.Fatal("expected a *UsageError")
+	}
+	if got.Flag != "port" || got.Value != "http" || got.Command != "tool serve" || !gotSubcommand {
+		t.Errorf("unexpected usage error %#v (subcommand %v)", got, gotSubcommand)
+	}
+}
+
+func TestAppRunUsageErrorDefault(t *testing.T) {
+	var buf bytes.Buffer
+	oldWriter := ErrWriter
+	defer func() { ErrWriter = oldWriter }()
+	ErrWriter = &buf
+
+	var handled error
+	app := &App{
+		Name:           "tool",
+		ExitErrHandler: func(c *Context, err error) { handled = err },
+	}
+	err := app.Run([]string{"tool", "--nope"})
+	if usageErr, ok := err.(*UsageError); !ok || usageErr.Flag != "nope" {
+		t.Fatalf("expected usage error for flag nope, got %#v", err)
+	}
+	if usageErr, ok := handled.(*UsageError); !ok || usageErr.Flag != "nope" || usageErr.ExitCode() != 2 {
+		t.Errorf("expected the handler to receive the usage error, got %#v", handled)
+	}
+	if strings.Count(buf.String(), "flag provided but not defined") != 1 {
+		t.Errorf("expected the error to be printed once, got %q", buf.String())
+	}
+	if !strings.Contains(buf.String(), "Run 'tool help' for usage.") {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+}
+
+func TestAppRunRequiredFlags(t *testing.T) {
+	t.Setenv("TOOL_TOKEN_TEST", "")
+	newApp := func() *App {
+		return &App{
+			Name: "tool",
+			Flags: []Flag{
+				StringFlag{Name: "host, H", Required: true},
+				StringFlag{Name: "token", EnvVar: "TOOL_TOKEN_TEST", Required: true},
+				StringFlag{Name: "user", Required: true},
+			},
+			Commands: []Command{
+				{
+					Name:   "deploy",
+					Flags:  []Flag{StringFlag{Name: "target", Required: true}},
+					Action: func(c *Context) error { return nil },
+				},
+			},
+			Action:         func(c *Context) error { return nil },
+			ExitErrHandler: func(c *Context, err error) {},
+		}
+	}
+
+	err := newApp().Run([]string{"tool"})
+	if multiErr, ok := err.(MultiError); !ok || len(multiErr.Errors) != 1 {
+		t.Fatalf("expected both missing flags in one MultiError, got %#v", err)
+	}
+	if expected := "Required flags \"host, user\" not set for \"tool\""; err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+
+	err = newApp().Run([]string{"tool", "--user", "me"})
+	multiErr, ok := err.(MultiError)
+	if !ok {
+		t.Fatalf("expected MultiError, got %#v", err)
+	}
+	expected := "Required flag \"host\" not set for \"tool\""
+	if len(multiErr.Errors) != 1 || multiErr.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, multiErr.Error())
+	}
+
+	if err := newApp().Run([]string{"tool", "-H", "h", "--user", "me"}); err != nil {
+		t.Errorf("expected parent to run without checking deploy flags, got %v", err)
+	}
+
+	err = newApp().Run([]string{"tool", "-H", "h", "--user", "me", "deploy"})
+	if err == nil || err.Error() != "Required flag \"target\" not set for \"tool deploy\"" {
+		t.Errorf("expected deploy required flag error, got %v", err)
+	}
+}
+
+func TestAppRunVersion(t *testing.T) {
+	oldReadBuildInfo := readBuildInfo
+	defer func() { readBuildInfo = oldReadBuildInfo }()
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{
+			GoVersion: "go1.24.2",
+			Main:      debug.Module{Version: "v1.2.3"},
+			Settings: []debug.BuildSetting{
+				{Key: "vcs.revision", Value: "0123456789abcdef"},
+				{Key: "vcs.modified", Value: "true"},
+				{Key: "vcs.time", Value: "2024-01-02T03:04:05Z"},
+			},
+		}, true
+	}
+
+	var out bytes.Buffer
+	app := &App{Name: "tool", Writer: &out}
+	if err := app.Run([]string{"tool", "--version"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "tool version v1.2.3 (0123456789ab-dirty, built 2024-01-02T03:04:05Z)\n"
+	if out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+
+	for _, args := range [][]string{
+		{"tool", "--version", "--json"},
+		{"tool", "-v", "-json"},
+		{"tool", "-v=json"},
+	} {
+		out.Reset()
+		app = &App{Name: "tool", Version: "v2.0.0", Writer: &out}
+		if err := app.Run(args); err != nil {
+			t.Fatalf("%v: unexpected error: %v", args, err)
+		}
+		var info BuildInfo
+		if err := json.Unmarshal(out.Bytes(), &info); err != nil {
+			t.Fatalf("%v: invalid JSON %q: %v", args, out.String(), err)
+		}
+		if info.Version != "v2.0.0" || info.Revision != "0123456789abcdef" || !info.Dirty {
+			t.Errorf("%v: unexpected build info %#v", args, info)
+		}
+	}
+
+	oldWriter := ErrWriter
+	defer func() { ErrWriter = oldWriter }()
+	ErrWriter = &bytes.Buffer{}
+	app = &App{Name: "tool", Writer: &bytes.Buffer{}, ExitErrHandler: func(c *Context, err error) {}}
+	if err := app.Run([]string{"tool", "--json", "--version"}); err == nil {
+		t.Error("expected --json to be rejected unless it directly follows --version")
+	}
+}
+
+func TestAppRunVersionFormat(t *testing.T) {
+	var out bytes.Buffer
+	var jsonValue string
+	app := &App{
+		Name:    "tool",
+		Version: "v2.0.0",
+		Writer:  &out,
+		Flags:   []Flag{StringFlag{Name: "json"}},
+		Action: func(c *Context) error {
+			jsonValue = c.String("json")
+			return nil
+		},
+		ExitErrHandler: func(c *Context, err error) {},
+	}
+	if err := app.Run([]string{"tool", "--version=text", "--json", "out.json"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := "tool version v2.0.0"; !strings.HasPrefix(out.String(), expected) {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+
+	out.Reset()
+	if err := app.Run([]string{"tool", "--json", "out.json"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Len() != 0 || jsonValue != "out.json" {
+		t.Errorf("expected the app's own json flag to be used, got output %q and value %q", out.String(), jsonValue)
+	}
+
+	oldWriter := ErrWriter
+	defer func() { ErrWriter = oldWriter }()
+	ErrWriter = &bytes.Buffer{}
+	err := app.Run([]string{"tool", "--version=yaml"})
+	if usageErr, ok := err.(*UsageError); !ok || usageErr.Flag != "version" || usageErr.Value != "yaml" {
+		t.Errorf("expected usage error for --version=yaml, got %#v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"text/template"
+)
+
+var bashCompletionTemplate = `#!/bin/bash
+# {{.Name}} bash completion
+
+_{{.FuncName}}_complete() {
+  local cur out directive value desc
+  local -a lines
+  COMPREPLY=()
+  cur="${COMP_WORDS[COMP_CWORD]}"
+  out=$("${COMP_WORDS[0]}" {{.Command}} "${COMP_WORDS[@]:1:$COMP_CWORD}" 2>/dev/null) || return
+  mapfile -t lines <<< "$out"
+  directive="${lines[-1]#:}"
+  unset 'lines[-1]'
+
+  if (( directive & {{.Error}} )); then
+    return
+  fi
+  if (( directive & {{.NoSpace}} )); then
+    compopt -o nospace
+  fi
+  if (( directive & {{.FilterFileExt}} )); then
+    local ext pattern=""
+    for ext in "${lines[@]}"; do
+      pattern+="${pattern:+|}*.${ext}"
+    done
+    compopt -o filenames
+    COMPREPLY=( $(compgen -f -X "!@(${pattern})" -- "$cur") $(compgen -d -- "$cur") )
+    return
+  fi
+  if (( directive & {{.FilterDirs}} )); then
+    compopt -o filenames
+    COMPREPLY=( $(compgen -d -- "$cur") )
+    return
+  fi
+
+  for value in "${lines[@]}"; do
+    value="${value%%$'\t'*}"
+    if [[ -n "$value" && "$value" == "$cur"* ]]; then
+      COMPREPLY+=( "$value" )
+    fi
+  done
+  if (( ${#COMPREPLY[@]} == 0 )) && ! (( directive & {{.NoFileComp}} )); then
+    compopt -o filenames
+    COMPREPLY=( $(compgen -f -- "$cur") )
+  fi
+}
+
+complete -F _{{.FuncName}}_complete {{.Name}}
+`
+
+var nonIdentifierChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+type completionScriptData struct {
+	Name          string
+	FuncName      string
+	Command       string
+	Error         CompletionDirective
+	NoSpace       CompletionDirective
+	NoFileComp    CompletionDirective
+	FilterFileExt CompletionDirective
+	FilterDirs    CompletionDirective
+}
+
+func (a *App) completionScriptData() completionScriptData {
+	return completionScriptData{
+		Name:          a.Name,
+		FuncName:      nonIdentifierChars.ReplaceAllString(a.Name, "_"),
+		Command:       completeCommand.Name,
+		Error:         CompletionDirectiveError,
+		NoSpace:       CompletionDirectiveNoSpace,
+		NoFileComp:    CompletionDirectiveNoFileComp,
+		FilterFileExt: CompletionDirectiveFilterFileExt,
+		FilterDirs:    CompletionDirectiveFilterDirs,
+	}
+}
+
+func (a *App) executeCompletionTemplate(name, text string) (string, error) {
+	a.Setup()
+	if a.Command(completeCommand.Name) == nil {
+		return "", fmt.Errorf("cannot generate %s completion for %s: EnableBashCompletion is not set", name, a.Name)
+	}
+	t, err := template.New(name).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var w bytes.Buffer
+	err = t.Execute(&w, a.completionScriptData())
+	return w.String(), err
+}
+
+func (a *App) ToBashCompletion() (string, error) {
+	return a.executeCompletionTemplate("bash", bashCompletionTemplate)
+}
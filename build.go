@@ -1 +1,202 @@
 package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+var VersionPrinter = printVersion
+
+type versionFlag struct {
+	BoolFlag
+}
+
+func (f versionFlag) Apply(set *flag.FlagSet) {
+	_ = f.ApplyWithError(set)
+}
+
+func (f versionFlag) ApplyWithError(set *flag.FlagSet) error {
+	val := &versionValue{}
+	eachName(f.Name, func(name string) {
+		set.Var(val, name, f.Usage)
+	})
+	return nil
+}
+
+func (f versionFlag) typeName() string {
+	return "BoolFlag"
+}
+
+type versionValue struct {
+	show   bool
+	format string
+}
+
+func (v *versionValue) Set(value string) error {
+	switch value {
+	case "text", "json":
+		v.show, v.format = true, value
+		return nil
+	}
+	show, err := strconv.ParseBool(value)
+	if err != nil {
+		return errors.New("expected true, false, text or json")
+	}
+	v.show, v.format = show, ""
+	return nil
+}
+
+func (v *versionValue) String() string {
+	if v == nil {
+		return "false"
+	}
+	return strconv.FormatBool(v.show)
+}
+
+func (v *versionValue) Get() interface{} {
+	return v.show
+}
+
+func (v *versionValue) IsBoolFlag() bool {
+	return true
+}
+
+var readBuildInfo = debug.ReadBuildInfo
+
+type BuildInfo struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Revision  string `json:"revision,omitempty"`
+	Dirty     bool   `json:"dirty"`
+	BuildTime string `json:"build_time,omitempty"`
+	GoVersion string `json:"go_version,omitempty"`
+}
+
+func (b BuildInfo) String() string {
+	var details []string
+	if b.Revision != "" {
+		revision := b.Revision
+		if len(revision) > 12 {
+			revision = revision[:12]
+		}
+		if b.Dirty {
+			revision += "-dirty"
+		}
+		details = append(details, revision)
+	}
+	if b.BuildTime != "" {
+		details = append(details, "built "+b.BuildTime)
+	}
+	if len(details) == 0 {
+		return b.Version
+	}
+	return fmt.Sprintf("%s (%s)", b.Version, strings.Join(details, ", "))
+}
+
+func (a *App) BuildInfo() BuildInfo {
+	b := BuildInfo{Name: a.Name, Version: a.Version}
+	info, ok := readBuildInfo()
+	if !ok {
+		return b
+	}
+	if b.Version == "" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		b.Version = info.Main.Version
+	}
+	b.GoVersion = info.GoVersion
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			b.Revision = setting.Value
+		case "vcs.modified":
+			b.Dirty = setting.Value == "true"
+		case "vcs.time":
+			b.BuildTime = setting.Value
+		}
+	}
+	if b.Version == "" {
+		b.Version = "devel"
+	}
+	return b
+}
+
+func ShowVersion(c *Context) {
+	VersionPrinter(c)
+}
+
+func printVersion(c *Context) {
+	fmt.Fprintf(c.App.Writer, "%v version %v\n", c.App.Name, c.App.BuildInfo())
+}
+
+func printVersionJSON(c *Context) error {
+	data, err := json.MarshalIndent(c.App.BuildInfo(), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(c.App.Writer, string(data))
+	return err
+}
+
+func checkVersion(c *Context) bool {
+	found := false
+	eachName(VersionFlag.GetName(), func(name string) {
+		if c.Bool(name) {
+			found = true
+		}
+	})
+	return found
+}
+
+func versionFormat(c *Context) string {
+	format := ""
+	eachName(VersionFlag.GetName(), func(name string) {
+		if f := c.flagSet.Lookup(name); f != nil {
+			if v, ok := f.Value.(*versionValue); ok && v.format != "" {
+				format = v.format
+			}
+		}
+	})
+	return format
+}
+
+func versionJSONArguments(a *App, arguments []string) []string {
+	if a.HideVersion || hasFlagName(a.Flags, "json") {
+		return arguments
+	}
+	for i := 0; i+1 < len(arguments); i++ {
+		arg := arguments[i]
+		if arg == "--" || a.Command(arg) != nil {
+			break
+		}
+		if next := arguments[i+1]; next != "-json" && next != "--json" {
+			continue
+		}
+		found := false
+		eachName(VersionFlag.GetName(), func(name string) {
+			if arg == "-"+name || arg == "--"+name {
+				found = true
+			}
+		})
+		if found {
+			rewritten := append(append([]string{}, arguments[:i]...), arg+"=json")
+			return append(rewritten, arguments[i+2:]...)
+		}
+	}
+	return arguments
+}
+
+func hasFlagName(flags []Flag, name string) bool {
+	found := false
+	for _, f := range flags {
+		eachName(f.GetName(), func(n string) {
+			if n == name {
+				found = true
+			}
+		})
+	}
+	return found
+}
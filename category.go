@@ -30,11 +30,25 @@ func (c CommandCategories) AddCommand(category string, command Command) CommandC
 }
 
 func (c *CommandCategory) VisibleCommands() []Command {
+	return visibleCommands(c.Commands)
+}
+
+func visibleCommands(commands Commands) []Command {
 	ret := []Command{}
-	for _, command := range c.Commands {
+	for _, command := range commands {
 		if !command.Hidden {
 			ret = append(ret, command)
 		}
 	}
 	return ret
 }
+
+func visibleCategories(categories CommandCategories) []*CommandCategory {
+	ret := []*CommandCategory{}
+	for _, category := range categories {
+		if len(category.VisibleCommands()) > 0 {
+			ret = append(ret, category)
+		}
+	}
+	return ret
+}
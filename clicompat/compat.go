@@ -0,0 +1,262 @@
+package clicompat
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"cli"
+)
+
+type Severity int
+
+const (
+	Additive Severity = iota
+	Deprecating
+	Breaking
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Additive:
+		return "additive"
+	case Deprecating:
+		return "deprecating"
+	case Breaking:
+		return "breaking"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+type Change struct {
+	Severity Severity
+	Command  string
+	Flag     string
+	Message  string
+}
+
+func (c Change) String() string {
+	var subject []string
+	if c.Command != "" {
+		subject = append(subject, "command '"+c.Command+"'")
+	}
+	if c.Flag != "" {
+		subject = append(subject, "flag '"+flagPrefix(c.Flag)+c.Flag+"'")
+	}
+	if len(subject) == 0 {
+		return fmt.Sprintf("%s: %s", c.Severity, c.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", c.Severity, strings.Join(subject, " "), c.Message)
+}
+
+func flagPrefix(name string) string {
+	if len(name) == 1 {
+		return "-"
+	}
+	return "--"
+}
+
+func Load(path string) (cli.Spec, error) {
+	var spec cli.Spec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return spec, err
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return spec, fmt.Errorf("could not parse %s as a CLI spec: %s", path, err)
+	}
+	return spec, nil
+}
+
+func HasBreaking(changes []Change) bool {
+	for _, change := range changes {
+		if change.Severity == Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+func Compare(oldSpec, newSpec cli.Spec) []Change {
+	var changes []Change
+	changes = append(changes, compareFlags("", oldSpec.Flags, newSpec.Flags)...)
+
+	matched := map[string]bool{}
+	for _, oldCommand := range oldSpec.Commands {
+		newCommand, ok := resolveCommand(newSpec.Commands, oldCommand.Path)
+		if !ok {
+			changes = append(changes, Change{Severity: Breaking, Command: oldCommand.Path, Message: "command was removed"})
+			continue
+		}
+		matched[newCommand.Path] = true
+		changes = append(changes, compareCommands(oldCommand, newCommand)...)
+	}
+	for _, newCommand := range newSpec.Commands {
+		if !matched[newCommand.Path] {
+			changes = append(changes, Change{Severity: Additive, Command: newCommand.Path, Message: "command was added"})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Severity > changes[j].Severity
+	})
+	return changes
+}
+
+func resolveCommand(commands []cli.CommandSpec, path string) (cli.CommandSpec, bool) {
+	var parent string
+	var found cli.CommandSpec
+	for _, name := range strings.Fields(path) {
+		ok := false
+		for _, command := range commands {
+			if commandParent(command.Path) != parent {
+				continue
+			}
+			if command.Name == name || containsString(command.Aliases, name) {
+				found, ok = command, true
+				break
+			}
+		}
+		if !ok {
+			return cli.CommandSpec{}, false
+		}
+		parent = found.Path
+	}
+	return found, true
+}
+
+func commandParent(path string) string {
+	if i := strings.LastIndex(path, " "); i >= 0 {
+		return path[:i]
+	}
+	return ""
+}
+
+func compareCommands(oldSpec, newSpec cli.CommandSpec) []Change {
+	var changes []Change
+	if oldSpec.Path != newSpec.Path {
+		changes = append(changes, Change{Severity: Deprecating, Command: oldSpec.Path,
+			Message: fmt.Sprintf("command was renamed to '%s' and is only reachable through an alias", newSpec.Path)})
+	}
+	oldNames := append([]string{oldSpec.Name}, oldSpec.Aliases...)
+	newNames := append([]string{newSpec.Name}, newSpec.Aliases...)
+	for _, name := range oldNames {
+		if !containsString(newNames, name) {
+			changes = append(changes, Change{Severity: Breaking, Command: oldSpec.Path, Message: fmt.Sprintf("alias '%s' was removed", name)})
+		}
+	}
+	for _, name := range newNames {
+		if !containsString(oldNames, name) {
+			changes = append(changes, Change{Severity: Additive, Command: oldSpec.Path, Message: fmt.Sprintf("alias '%s' was added", name)})
+		}
+	}
+	if !oldSpec.Hidden && newSpec.Hidden {
+		changes = append(changes, Change{Severity: Deprecating, Command: oldSpec.Path, Message: "command is now hidden"})
+	}
+	if oldSpec.Deprecated == "" && newSpec.Deprecated != "" {
+		changes = append(changes, Change{Severity: Deprecating, Command: oldSpec.Path, Message: "command was deprecated: " + newSpec.Deprecated})
+	}
+	return append(changes, compareFlags(oldSpec.Path, oldSpec.Flags, newSpec.Flags)...)
+}
+
+func compareFlags(command string, oldFlags, newFlags []cli.FlagSpec) []Change {
+	var changes []Change
+	matched := map[int]bool{}
+	for _, oldFlag := range oldFlags {
+		i := resolveFlag(newFlags, oldFlag)
+		if i < 0 {
+			changes = append(changes, Change{Severity: Breaking, Command: command, Flag: oldFlag.Name, Message: "flag was removed"})
+			continue
+		}
+		matched[i] = true
+		changes = append(changes, compareFlag(command, oldFlag, newFlags[i])...)
+	}
+	for i, newFlag := range newFlags {
+		if matched[i] {
+			continue
+		}
+		if newFlag.Required {
+			changes = append(changes, Change{Severity: Breaking, Command: command, Flag: newFlag.Name, Message: "required flag was added"})
+		} else {
+			changes = append(changes, Change{Severity: Additive, Command: command, Flag: newFlag.Name, Message: "flag was added"})
+		}
+	}
+	return changes
+}
+
+func resolveFlag(flags []cli.FlagSpec, flag cli.FlagSpec) int {
+	for i, f := range flags {
+		if f.Name == flag.Name {
+			return i
+		}
+	}
+	for i, f := range flags {
+		for _, name := range flag.Names {
+			if containsString(f.Names, name) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func compareFlag(command string, oldSpec, newSpec cli.FlagSpec) []Change {
+	var changes []Change
+	add := func(severity Severity, format string, args ...interface{}) {
+		changes = append(changes, Change{Severity: severity, Command: command, Flag: oldSpec.Name, Message: fmt.Sprintf(format, args...)})
+	}
+	for _, name := range oldSpec.Names {
+		if !containsString(newSpec.Names, name) {
+			add(Breaking, "name '%s' was removed", name)
+		}
+	}
+	for _, name := range newSpec.Names {
+		if !containsString(oldSpec.Names, name) {
+			add(Additive, "name '%s' was added", name)
+		}
+	}
+	if oldSpec.TakesValue != newSpec.TakesValue {
+		add(Breaking, "flag no longer takes the same kind of value")
+	} else if oldSpec.Type != newSpec.Type {
+		add(Breaking, "type changed from %s to %s", oldSpec.Type, newSpec.Type)
+	}
+	if oldSpec.Default != newSpec.Default {
+		add(Breaking, "default changed from %q to %q", oldSpec.Default, newSpec.Default)
+	}
+	if !oldSpec.Required && newSpec.Required {
+		add(Breaking, "flag became required")
+	}
+	if oldSpec.Required && !newSpec.Required {
+		add(Additive, "flag is no longer required")
+	}
+	for _, env := range oldSpec.EnvVars {
+		if !containsString(newSpec.EnvVars, env) {
+			add(Breaking, "environment variable %s was removed", env)
+		}
+	}
+	for _, env := range newSpec.EnvVars {
+		if !containsString(oldSpec.EnvVars, env) {
+			add(Additive, "environment variable %s was added", env)
+		}
+	}
+	for _, path := range oldSpec.FilePaths {
+		if !containsString(newSpec.FilePaths, path) {
+			add(Breaking, "file %s is no longer read", path)
+		}
+	}
+	if !oldSpec.Hidden && newSpec.Hidden {
+		add(Deprecating, "flag is now hidden")
+	}
+	return changes
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package clicompat
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cli"
+)
+
+func newSpecApp(modify func(app *cli.App)) cli.Spec {
+	app := &cli.App{
+		Name:    "tool",
+		Version: "v1.0.0",
+		Flags: []cli.Flag{
+			cli.BoolFlag{Name: "verbose"},
+		},
+		Commands: []cli.Command{
+			{
+				Name: "remote",
+				Subcommands: []cli.Command{
+					{
+						Name: "add",
+						Flags: []cli.Flag{
+							cli.StringFlag{Name: "branch, b", Value: "main"},
+							cli.StringFlag{Name: "token"},
+						},
+					},
+					{Name: "remove", Aliases: []string{"rm"}},
+				},
+			},
+			{Name: "status"},
+		},
+	}
+	if modify != nil {
+		modify(app)
+	}
+	app.Setup()
+	return app.Spec()
+}
+
+func changeStrings(changes []Change) string {
+	var lines []string
+	for _, change := range changes {
+		lines = append(lines, change.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestCompareUnchanged(t *testing.T) {
+	changes := Compare(newSpecApp(nil), newSpecApp(nil))
+	if len(changes) != 0 {
+		t.Errorf("expected no changes, got:\n%s", changeStrings(changes))
+	}
+}
+
+func TestCompareClassifiesChanges(t *testing.T) {
+	newSpec := newSpecApp(func(app *cli.App) {
+		app.Flags = append(app.Flags, cli.StringFlag{Name: "profile"}, cli.BoolFlag{Name: "q"})
+		app.Commands[0].Subcommands[0].Flags = []cli.Flag{
+			cli.StringFlag{Name: "branch, b", Value: "trunk"},
+			cli.StringFlag{Name: "token", Required: true},
+		}
+		app.Commands[0].Subcommands[1] = cli.Command{Name: "delete", Aliases: []string{"remove"}}
+		app.Commands[1].Deprecated = "use 'tool remote' instead"
+		app.Commands = append(app.Commands, cli.Command{Name: "sync"})
+	})
+	changes := Compare(newSpecApp(nil), newSpec)
+	output := changeStrings(changes)
+	for _, expected := range []string{
+		"breaking: command 'remote add' flag '--branch': default changed from \"main\" to \"trunk\"",
+		"breaking: command 'remote add' flag '--token': flag became required",
+		"breaking: command 'remote remove': alias 'rm' was removed",
+		"deprecating: command 'remote remove': command was renamed to 'remote delete' and is only reachable through an alias",
+		"deprecating: command 'status': command was deprecated: use 'tool remote' instead",
+		"additive: flag '--profile': flag was added",
+		"additive: flag '-q': flag was added",
+		"additive: command 'sync': command was added",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected %q in:\n%s", expected, output)
+		}
+	}
+	if !HasBreaking(changes) {
+		t.Error("expected breaking changes")
+	}
+	if changes[0].Severity != Breaking || changes[len(changes)-1].Severity != Additive {
+		t.Errorf("expected changes ordered by severity, got:\n%s", output)
+	}
+}
+
+func TestCompareRemovals(t *testing.T) {
+	newSpec := newSpecApp(func(app *cli.App) {
+		app.Flags = nil
+		app.Commands = app.Commands[:1]
+		app.Commands[0].Subcommands[0].Flags = []cli.Flag{
+			cli.StringFlag{Name: "branch", Value: "main"},
+			cli.StringFlag{Name: "token"},
+		}
+	})
+	output := changeStrings(Compare(newSpecApp(nil), newSpec))
+	for _, expected := range []string{
+		"breaking: flag '--verbose': flag was removed",
+		"breaking: command 'status': command was removed",
+		"breaking: command 'remote add' flag '--branch': name 'b' was removed",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected %q in:\n%s", expected, output)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "spec.json")
+	data, _ := json.Marshal(newSpecApp(nil))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if spec.Name != "tool" || len(spec.Commands) == 0 {
+		t.Errorf("unexpected spec: %+v", spec)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "could not parse") {
+		t.Errorf("expected parse error, got %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"cli"
+	"cli/clicompat"
+)
+
+func main() {
+	app := &cli.App{
+		Name:  "clicompat",
+		Usage: "report breaking changes between two JSON CLI specs",
+		Flags: []cli.Flag{
+			cli.StringFlag{Name: "old", Usage: "spec of the previous release read from `FILE`", Required: true, TakesFile: true},
+			cli.StringFlag{Name: "new", Usage: "spec of the candidate release read from `FILE`", Required: true, TakesFile: true},
+			cli.BoolFlag{Name: "quiet, q", Usage: "only report breaking changes"},
+		},
+		Action: func(c *cli.Context) error {
+			oldSpec, err := clicompat.Load(c.String("old"))
+			if err != nil {
+				return cli.NewExitError(err, 2)
+			}
+			newSpec, err := clicompat.Load(c.String("new"))
+			if err != nil {
+				return cli.NewExitError(err, 2)
+			}
+			changes := clicompat.Compare(oldSpec, newSpec)
+			for _, change := range changes {
+				if c.Bool("quiet") && change.Severity != clicompat.Breaking {
+					continue
+				}
+				fmt.Fprintln(c.App.Writer, change)
+			}
+			if clicompat.HasBreaking(changes) {
+				return cli.NewExitError("breaking changes found", 1)
+			}
+			return nil
+		},
+	}
+	if err := app.Run(os.Args); err != nil {
+		os.Exit(1)
+	}
+}
@@ -1,7 +1,169 @@
 package cli
 
+import (
+	"sort"
+	"strings"
+)
+
 type Command struct {
-	Hidden bool
+	Name            string
+	Aliases         []string
+	HelpName        string
+	Usage           string
+	UsageText       string
+	ArgsUsage       string
+	Description     string
+	Category        string
+	Deprecated      string
+	Flags           []Flag
+	OnUsageError    OnUsageErrorFunc
+	Before          BeforeFunc
+	Action          ActionFunc
+	BashComplete    BashCompleteFunc
+	After           AfterFunc
+	Subcommands     Commands
+	SkipFlagParsing bool
+	HideHelp        bool
+	Hidden          bool
 }
 
 type Commands []Command
+
+func (c Command) Run(ctx *Context) (err error) {
+	c = c.withHelp()
+	if c.HelpName == "" {
+		c.HelpName = helpNameFor(ctx) + " " + c.Name
+	}
+	set, err := flagSet(c.Name, c.Flags)
+	if err != nil {
+		return err
+	}
+	args := ctx.Args().Tail()
+	if c.SkipFlagParsing {
+		args = append([]string{"--"}, args...)
+	}
+	err = set.Parse(args)
+	if err == nil {
+		err = normalizeFlags(c.Flags, set)
+	}
+	context := NewContext(ctx.App, set, ctx)
+	context.Command = c
+	if err != nil {
+		if context.shellComplete {
+			ShowCompletions(context)
+			return nil
+		}
+		onUsageError := c.OnUsageError
+		if onUsageError == nil {
+			onUsageError = ctx.App.OnUsageError
+		}
+		return ctx.App.handleUsageError(context, onUsageError, err, true)
+	}
+
+	if checkCompletions(context, c.Subcommands) {
+		return nil
+	}
+
+	if !c.HideHelp && (checkHelp(context) || leafHelpRequested(c, context.Args().Slice())) {
+		return showCommandHelp(ctx.App.Writer, c)
+	}
+
+	if !context.shellComplete && (c.HideHelp || !helpRequested(context, c.Subcommands)) {
+		if err = checkRequiredFlags(c.Flags, context); err != nil {
+			ctx.App.handleExitCoder(context, err)
+			return err
+		}
+	}
+
+	if c.After != nil {
+		defer func() {
+			if afterErr := c.After(context); afterErr != nil {
+				ctx.App.handleExitCoder(context, afterErr)
+				if err != nil {
+					err = NewMultiError(err, afterErr)
+				} else {
+					err = afterErr
+				}
+			}
+		}()
+	}
+
+	if c.Before != nil {
+		if beforeErr := c.Before(context); beforeErr != nil {
+			ctx.App.handleExitCoder(context, beforeErr)
+			err = beforeErr
+			return err
+		}
+	}
+
+	if name := context.Args().First(); name != "" {
+		if sub := c.Subcommands.Command(name); sub != nil {
+			return sub.Run(context)
+		}
+		if c.Action == nil && len(c.Subcommands) > 0 {
+			return ctx.App.commandNotFound(context, c.Subcommands, name)
+		}
+	}
+
+	if c.Action == nil {
+		return showCommandHelp(ctx.App.Writer, c)
+	}
+	err = c.Action(context)
+	ctx.App.handleExitCoder(context, err)
+	return err
+}
+
+func (c Command) withHelp() Command {
+	if c.HideHelp {
+		return c
+	}
+	c.Flags = append(c.Flags[:len(c.Flags):len(c.Flags)], HelpFlag)
+	if len(c.Subcommands) > 0 && c.Subcommands.Command(helpCommand.Name) == nil {
+		c.Subcommands = append(c.Subcommands[:len(c.Subcommands):len(c.Subcommands)], helpCommand)
+	}
+	return c
+}
+
+func (c Command) VisibleFlags() []Flag {
+	return visibleFlags(c.Flags)
+}
+
+func (c Command) VisibleCommands() []Command {
+	return visibleCommands(c.Subcommands)
+}
+
+func (c Command) VisibleCategories() []*CommandCategory {
+	categories := CommandCategories{}
+	for _, command := range c.Subcommands {
+		categories = categories.AddCommand(command.Category, command)
+	}
+	sort.Sort(categories)
+	return visibleCategories(categories)
+}
+
+func (c Command) Names() []string {
+	names := []string{c.Name}
+	return append(names, c.Aliases...)
+}
+
+func (c Command) HasName(name string) bool {
+	for _, n := range c.Names() {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Command) FullName() string {
+	return strings.Join(c.Names(), ", ")
+}
+
+func (c Commands) Command(name string) *Command {
+	for i := range c {
+		if c[i].HasName(name) {
+			return &c[i]
+		}
+	}
+	return nil
+}
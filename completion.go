@@ -0,0 +1,285 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func checkShellCompleteFlag(a *App, arguments []string) (bool, []string, string) {
+	if !a.EnableBashCompletion || len(arguments) == 0 {
+		return false, arguments, ""
+	}
+	if arguments[len(arguments)-1] != "--"+BashCompletionFlag.GetName() {
+		return false, arguments, ""
+	}
+	arguments = arguments[:len(arguments)-1]
+	if last := len(arguments) - 1; last >= 0 && strings.HasPrefix(arguments[last], "-") {
+		return true, arguments[:last], arguments[last]
+	}
+	return true, arguments, ""
+}
+
+func checkCompletions(c *Context, commands Commands) bool {
+	if !c.shellComplete {
+		return false
+	}
+	if name := c.flagSet.Arg(0); name != "" && commands.Command(name) != nil {
+		return false
+	}
+	ShowCompletions(c)
+	return true
+}
+
+func ShowCompletions(c *Context) {
+	complete := c.App.BashComplete
+	if c.Command.Name != "" {
+		complete = c.Command.BashComplete
+	}
+	if complete == nil {
+		complete = DefaultCompleteWithFlags
+	}
+	complete(c)
+}
+
+func DefaultCompleteWithFlags(c *Context) {
+	flags, commands := c.App.Flags, c.App.Commands
+	if c.Command.Name != "" {
+		flags, commands = c.Command.Flags, c.Command.Subcommands
+	}
+	if strings.HasPrefix(c.completeWord, "-") {
+		printFlagSuggestions(c.App.Writer, c.completeWord, flags)
+		return
+	}
+	printCommandSuggestions(c.App.Writer, commands)
+}
+
+func printCommandSuggestions(w io.Writer, commands Commands) {
+	for _, command := range commands {
+		if command.Hidden {
+			continue
+		}
+		for _, name := range command.Names() {
+			fmt.Fprintln(w, name)
+		}
+	}
+}
+
+func printFlagSuggestions(w io.Writer, lastArg string, flags []Flag) {
+	for _, f := range visibleFlags(flags) {
+		eachName(f.GetName(), func(name string) {
+			if candidate := prefixFor(name) + name; strings.HasPrefix(candidate, lastArg) {
+				fmt.Fprintln(w, candidate)
+			}
+		})
+	}
+}
+
+type CompletionDirective int
+
+const (
+	CompletionDirectiveError CompletionDirective = 1 << iota
+	CompletionDirectiveNoSpace
+	CompletionDirectiveNoFileComp
+	CompletionDirectiveFilterFileExt
+	CompletionDirectiveFilterDirs
+	CompletionDirectiveDefault CompletionDirective = 0
+)
+
+var completeCommand = Command{
+	Name:            "__complete",
+	Usage:           "print completion candidates for a partial command line",
+	Hidden:          true,
+	HideHelp:        true,
+	SkipFlagParsing: true,
+}
+
+func init() {
+	completeCommand.Action = completeAction
+}
+
+func completeAction(c *Context) error {
+	args := c.Args().Slice()
+	toComplete := ""
+	if len(args) > 0 {
+		toComplete, args = args[len(args)-1], args[:len(args)-1]
+	}
+	ctx, args, ok := completionContext(c.App, args)
+	ctx.completeWord = toComplete
+	directive := CompletionDirectiveError
+	if ok {
+		directive = writeCompletions(ctx, args, toComplete)
+	}
+	_, err := fmt.Fprintf(c.App.Writer, ":%d\n", directive)
+	return err
+}
+
+func (c *Context) SetCompletionDirective(directive CompletionDirective) {
+	c.directive = &directive
+}
+
+func completionDirective(c *Context, fallback CompletionDirective) CompletionDirective {
+	if c.directive != nil {
+		return *c.directive
+	}
+	return fallback
+}
+
+func completionContext(a *App, args []string) (*Context, []string, bool) {
+	set, err := flagSet(a.Name, a.Flags)
+	if err != nil {
+		set = flag.NewFlagSet(a.Name, flag.ContinueOnError)
+	}
+	ctx := NewContext(a, set, nil)
+	ctx.shellComplete = true
+	for {
+		flags, commands := completionLevel(ctx)
+		err := set.Parse(args)
+		_ = normalizeFlags(flags, set)
+		if err != nil {
+			return ctx, args, pendingFlag(flags, args) != nil && strings.HasPrefix(err.Error(), "flag needs an argument")
+		}
+		command := commands.Command(set.Arg(0))
+		if command == nil {
+			action := a.Action
+			if ctx.Command.Name != "" {
+				action = ctx.Command.Action
+			}
+			return ctx, args, set.NArg() == 0 || action != nil || len(visibleCommands(commands)) == 0
+		}
+		cmd := command.withHelp()
+		cmd.HelpName = helpNameFor(ctx) + " " + cmd.Name
+		args = set.Args()[1:]
+		if cmd.SkipFlagParsing {
+			args = append([]string{"--"}, args...)
+		}
+		if set, err = flagSet(cmd.Name, cmd.Flags); err != nil {
+			return ctx, args, false
+		}
+		ctx = NewContext(a, set, ctx)
+		ctx.Command = cmd
+	}
+}
+
+func completionLevel(c *Context) ([]Flag, Commands) {
+	if c.Command.Name != "" {
+		return c.Command.Flags, c.Command.Subcommands
+	}
+	return c.App.Flags, c.App.Commands
+}
+
+func writeCompletions(c *Context, args []string, toComplete string) CompletionDirective {
+	w := c.App.Writer
+	flags, commands := completionLevel(c)
+	if strings.HasPrefix(toComplete, "-") {
+		if i := strings.Index(toComplete, "="); i >= 0 {
+			f := lookupFlag(flags, strings.TrimLeft(toComplete[:i], "-"))
+			return writeFlagValueCompletions(c, f, toComplete[:i+1], toComplete[i+1:])
+		}
+		for _, f := range visibleFlags(flags) {
+			var usage string
+			if df, ok := f.(DocGenerationFlag); ok {
+				_, usage = unquoteUsage(df.GetUsage())
+			}
+			eachName(f.GetName(), func(name string) {
+				if candidate := prefixFor(name) + name; strings.HasPrefix(candidate, toComplete) {
+					writeCompletion(w, candidate, usage)
+				}
+			})
+		}
+		return CompletionDirectiveNoFileComp
+	}
+	if len(args) > 1 && args[len(args)-1] == "=" {
+		args = args[:len(args)-1]
+	}
+	if f := pendingFlag(flags, args); f != nil {
+		return writeFlagValueCompletions(c, f, "", toComplete)
+	}
+
+	complete := c.App.BashComplete
+	action := c.App.Action
+	if c.Command.Name != "" {
+		complete, action = c.Command.BashComplete, c.Command.Action
+	}
+	if complete != nil {
+		complete(c)
+		return completionDirective(c, CompletionDirectiveDefault)
+	}
+	for _, command := range commands {
+		if command.Hidden {
+			continue
+		}
+		for i, name := range command.Names() {
+			if strings.HasPrefix(name, toComplete) && (i == 0 || toComplete != "") {
+				writeCompletion(w, name, command.Usage)
+			}
+		}
+	}
+	if action == nil && len(visibleCommands(commands)) > 0 {
+		return CompletionDirectiveNoFileComp
+	}
+	return CompletionDirectiveDefault
+}
+
+func writeFlagValueCompletions(c *Context, f Flag, prefix, partial string) CompletionDirective {
+	if f == nil {
+		return CompletionDirectiveDefault
+	}
+	if complete := flagCompleter(f); complete != nil {
+		for _, value := range complete(c, partial) {
+			writeCompletion(c.App.Writer, prefix+value, "")
+		}
+	}
+	if flagTakesFile(f) {
+		return completionDirective(c, CompletionDirectiveDefault)
+	}
+	return completionDirective(c, CompletionDirectiveNoFileComp)
+}
+
+func pendingFlag(flags []Flag, args []string) Flag {
+	if len(args) == 0 {
+		return nil
+	}
+	last := args[len(args)-1]
+	if !strings.HasPrefix(last, "-") || strings.Contains(last, "=") {
+		return nil
+	}
+	f := lookupFlag(flags, strings.TrimLeft(last, "-"))
+	if df, ok := f.(DocGenerationFlag); ok && df.TakesValue() {
+		return f
+	}
+	return nil
+}
+
+func flagCompleter(f Flag) FlagCompleteFunc {
+	field := flagValue(f).FieldByName("Complete")
+	if !field.IsValid() || field.IsNil() {
+		return nil
+	}
+	complete, _ := field.Interface().(FlagCompleteFunc)
+	return complete
+}
+
+func writeCompletion(w io.Writer, value, description string) {
+	if description == "" {
+		fmt.Fprintln(w, value)
+		return
+	}
+	fmt.Fprintf(w, "%s\t%s\n", value, description)
+}
+
+func lookupFlag(flags []Flag, name string) Flag {
+	for _, f := range flags {
+		found := false
+		eachName(f.GetName(), func(n string) {
+			if n == name {
+				found = true
+			}
+		})
+		if found {
+			return f
+		}
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var completionShells = []string{"bash", "zsh", "fish", "powershell"}
+
+func completionScript(a *App, shell string) (string, error) {
+	switch shell {
+	case "bash":
+		return a.ToBashCompletion()
+	case "zsh":
+		return a.ToZshCompletion()
+	case "fish":
+		return a.ToFishCompletion()
+	case "powershell":
+		return a.ToPowerShellCompletion()
+	}
+	return "", NewExitError(fmt.Sprintf("unsupported shell '%s', expected one of %s", shell, strings.Join(completionShells, ", ")), 1)
+}
+
+func completionInstallPath(a *App, shell, dir string) (string, error) {
+	var file string
+	switch shell {
+	case "bash":
+		file = a.Name
+	case "zsh":
+		file = "_" + a.Name
+	case "fish":
+		file = a.Name + ".fish"
+	case "powershell":
+		file = a.Name + ".ps1"
+	default:
+		return "", NewExitError(fmt.Sprintf("unsupported shell '%s', expected one of %s", shell, strings.Join(completionShells, ", ")), 1)
+	}
+	if dir != "" {
+		return filepath.Join(dir, file), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		configHome = filepath.Join(home, ".config")
+	}
+	switch shell {
+	case "bash":
+		dir = filepath.Join(dataHome, "bash-completion", "completions")
+	case "zsh":
+		dir = filepath.Join(dataHome, "zsh", "site-functions")
+	case "fish":
+		dir = filepath.Join(configHome, "fish", "completions")
+	case "powershell":
+		dir = filepath.Join(configHome, "powershell", "completions")
+	}
+	return filepath.Join(dir, file), nil
+}
+
+func powerShellProfilePath() (string, error) {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		configHome = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configHome, "powershell", "Microsoft.PowerShell_profile.ps1"), nil
+}
+
+func writeFileIfChanged(path string, content []byte) (bool, error) {
+	if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, content) {
+		return false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(path, content, 0644)
+}
+
+func appendLineIfMissing(path, line string) (bool, error) {
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	for _, l := range strings.Split(string(existing), "\n") {
+		if strings.TrimSpace(l) == line {
+			return false, nil
+		}
+	}
+	if len(existing) > 0 && !bytes.HasSuffix(existing, []byte("\n")) {
+		existing = append(existing, '\n')
+	}
+	return writeFileIfChanged(path, append(existing, line+"\n"...))
+}
+
+func installCompletion(c *Context, shell, dir string) error {
+	script, err := completionScript(c.App, shell)
+	if err != nil {
+		return err
+	}
+	path, err := completionInstallPath(c.App, shell, dir)
+	if err != nil {
+		return err
+	}
+	changed, err := writeFileIfChanged(path, []byte(script))
+	if err != nil {
+		return err
+	}
+	if changed {
+		fmt.Fprintf(c.App.Writer, "Installed %s completion to %s\n", shell, path)
+	} else {
+		fmt.Fprintf(c.App.Writer, "%s completion in %s is up to date\n", shell, path)
+	}
+	switch shell {
+	case "zsh":
+		fmt.Fprintf(c.App.Writer, "Make sure %s is in your $fpath before compinit runs\n", filepath.Dir(path))
+	case "powershell":
+		if dir != "" {
+			break
+		}
+		profile, err := powerShellProfilePath()
+		if err != nil {
+			return err
+		}
+		changed, err := appendLineIfMissing(profile, fmt.Sprintf(". '%s'", powerShellEscape(path)))
+		if err != nil {
+			return err
+		}
+		if changed {
+			fmt.Fprintf(c.App.Writer, "Added %s to %s\n", path, profile)
+		}
+	}
+	return nil
+}
+
+func newCompletionCommand() Command {
+	var subcommands Commands
+	for _, shell := range completionShells {
+		subcommands = append(subcommands, Command{
+			Name:  shell,
+			Usage: fmt.Sprintf("print the %s completion script", shell),
+			Action: func(c *Context) error {
+				script, err := completionScript(c.App, shell)
+				if err != nil {
+					return err
+				}
+				_, err = fmt.Fprint(c.App.Writer, script)
+				return err
+			},
+		})
+	}
+	subcommands = append(subcommands, Command{
+		Name:      "install",
+		Usage:     "install the completion script for a shell",
+		ArgsUsage: "[" + strings.Join(completionShells, "|") + "]",
+		Flags: []Flag{
+			StringFlag{
+				Name:      "dir",
+				Usage:     "write the script into `DIR` instead of the shell's default location",
+				TakesFile: true,
+			},
+		},
+		Action: func(c *Context) error {
+			shell := c.Args().First()
+			if shell == "" {
+				shell = filepath.Base(os.Getenv("SHELL"))
+			}
+			if shell == "pwsh" {
+				shell = "powershell"
+			}
+			return installCompletion(c, shell, c.String("dir"))
+		},
+	})
+	return Command{
+		Name:        "completion",
+		Usage:       "print or install shell completion scripts",
+		Subcommands: subcommands,
+	}
+}
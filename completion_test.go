@@ -0,0 +1,313 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newCompletionTestApp(out *bytes.Buffer) *App {
+	return &App{
+		Name:                 "tool",
+		Writer:               out,
+		EnableBashCompletion: true,
+		HideVersion:          true,
+		Flags:                []Flag{BoolFlag{Name: "verbose, V"}},
+		Commands: []Command{
+			{Name: "status", Aliases: []string{"st"}, Action: func(c *Context) error { return nil }},
+			{Name: "secret", Hidden: true},
+			{
+				Name: "remote",
+				Subcommands: []Command{
+					{
+						Name:   "add",
+						Flags:  []Flag{StringFlag{Name: "name"}, IntFlag{Name: "depth"}, BoolFlag{Name: "dry-run", Hidden: true}},
+						Action: func(c *Context) error { panic("action must not run") },
+					},
+					{
+						Name: "remove",
+						BashComplete: func(c *Context) {
+							c.App.Writer.Write([]byte("origin\nupstream\n"))
+						},
+						Action: func(c *Context) error { panic("action must not run") },
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestBashCompletion(t *testing.T) {
+	cases := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"tool"}, "status\nst\nremote\nhelp\nh\n"},
+		{[]string{"tool", "-"}, "--verbose\n-V\n--help\n-h\n"},
+		{[]string{"tool", "--verbose", "remote"}, "add\nremove\nhelp\nh\n"},
+		{[]string{"tool", "remote", "add", "--d"}, "--depth\n"},
+		{[]string{"tool", "remote", "remove"}, "origin\nupstream\n"},
+	}
+	for _, c := range cases {
+		var out bytes.Buffer
+		args := append(c.args, "--generate-bash-completion")
+		if err := newCompletionTestApp(&out).Run(args); err != nil {
+			t.Fatalf("%v: unexpected error: %v", c.args, err)
+		}
+		if out.String() != c.expected {
+			t.Errorf("%v: expected %q, got %q", c.args, c.expected, out.String())
+		}
+	}
+}
+
+func TestCompleteCommand(t *testing.T) {
+	cases := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{""}, "status\tshow status\nremote\nhelp\tShows a list of commands or help for one command\n:4\n"},
+		{[]string{"st"}, "status\tshow status\nst\tshow status\n:4\n"},
+		{[]string{"--v"}, "--verbose\tbe loud\n:4\n"},
+		{[]string{"--config", ""}, ":0\n"},
+		{[]string{"--verbose", "remote", "add", "--d"}, "--depth\tclone depth\n:4\n"},
+		{[]string{"remote", "add", "--name", ""}, ":4\n"},
+		{[]string{"remote", "remove", "o"}, "origin\nupstream\n:0\n"},
+	}
+	for _, c := range cases {
+		var out bytes.Buffer
+		app := newCompletionTestApp(&out)
+		app.Commands[0].Usage = "show status"
+		app.Commands[2].Subcommands[0].Flags[1] = IntFlag{Name: "depth", Usage: "clone depth"}
+		app.Flags = append(app.Flags, StringFlag{Name: "config", TakesFile: true})
+		app.Flags[0] = BoolFlag{Name: "verbose, V", Usage: "be loud"}
+		if err := app.Run(append([]string{"tool", "__complete"}, c.args...)); err != nil {
+			t.Fatalf("%v: unexpected error: %v", c.args, err)
+		}
+		if out.String() != c.expected {
+			t.Errorf("%v: expected %q, got %q", c.args, c.expected, out.String())
+		}
+	}
+}
+
+func TestCompleteCommandSkipsHooks(t *testing.T) {
+	var out bytes.Buffer
+	afterRan := false
+	app := newCompletionTestApp(&out)
+	app.Before = func(c *Context) error {
+		fmt.Fprintln(c.App.Writer, "banner")
+		return errors.New("no config")
+	}
+	app.After = func(c *Context) error {
+		afterRan = true
+		return nil
+	}
+	if err := app.Run([]string{"tool", "__complete", "st"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := "status\nst\n:4\n"; out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+	if afterRan {
+		t.Error("expected After not to run for __complete")
+	}
+}
+
+func TestCompletionScripts(t *testing.T) {
+	app := newCompletionTestApp(nil)
+	cases := []struct {
+		generate func() (string, error)
+		expected string
+	}{
+		{app.ToBashCompletion, "complete -F _tool_complete tool\n"},
+		{app.ToFishCompletion, "complete -c tool -f -a '(__tool_complete)'\n"},
+		{app.ToZshCompletion, "  compdef _tool tool\n"},
+	}
+	for _, c := range cases {
+		script, err := c.generate()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(script, " __complete ") || !strings.Contains(script, c.expected) {
+			t.Errorf("expected a wrapper around __complete containing %q, got:\n%s", c.expected, script)
+		}
+	}
+}
+
+func TestCompletionScriptsRequireCompletion(t *testing.T) {
+	app := &App{Name: "tool"}
+	for _, generate := range []func() (string, error){app.ToBashCompletion, app.ToFishCompletion, app.ToZshCompletion} {
+		script, err := generate()
+		if err == nil || !strings.Contains(err.Error(), "EnableBashCompletion is not set") || script != "" {
+			t.Errorf("expected an error without EnableBashCompletion, got %q and %v", script, err)
+		}
+	}
+}
+
+func TestToPowerShellCompletion(t *testing.T) {
+	app := newCompletionTestApp(nil)
+	app.Commands[0].Usage = "show the working tree's status"
+	app.Commands[2].Aliases = []string{"rm"}
+	script, err := app.ToPowerShellCompletion()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected, err := os.ReadFile("testdata/powershell_completion.ps1")
+	if err != nil {
+		t.Fatalf("unable to read golden file: %v", err)
+	}
+	if script != string(expected) {
+		t.Errorf("expected script:\n%s\ngot:\n%s", expected, script)
+	}
+}
+
+func TestCompleteFlagValues(t *testing.T) {
+	clusters := func(c *Context, partial string) []string {
+		if c.String("region") == "eu" {
+			return []string{"eu-1", "eu-2"}
+		}
+		return []string{"us-1"}
+	}
+	cases := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"deploy", "--region", "eu", "--cluster", ""}, "eu-1\neu-2\n:4\n"},
+		{[]string{"deploy", "-r", "eu", "-c", "eu"}, "eu-1\neu-2\n:4\n"},
+		{[]string{"deploy", "--cluster", "=", ""}, "us-1\n:4\n"},
+		{[]string{"deploy", "--region=eu", "--cluster=e"}, "--cluster=eu-1\n--cluster=eu-2\n:4\n"},
+		{[]string{"deploy", "--manifest", ""}, "deploy.yml\n:0\n"},
+	}
+	for _, c := range cases {
+		var out bytes.Buffer
+		app := &App{
+			Name:                 "tool",
+			Writer:               &out,
+			EnableBashCompletion: true,
+			Commands: []Command{
+				{
+					Name: "deploy",
+					Flags: []Flag{
+						StringFlag{Name: "region, r"},
+						StringFlag{Name: "cluster, c", Complete: clusters},
+						StringSliceFlag{
+							Name:      "manifest",
+							TakesFile: true,
+							Complete: func(c *Context, partial string) []string {
+								return []string{"deploy.yml"}
+							},
+						},
+					},
+					Action: func(c *Context) error { return nil },
+				},
+			},
+		}
+		if err := app.Run(append([]string{"tool", "__complete"}, c.args...)); err != nil {
+			t.Fatalf("%v: unexpected error: %v", c.args, err)
+		}
+		if out.String() != c.expected {
+			t.Errorf("%v: expected %q, got %q", c.args, c.expected, out.String())
+		}
+	}
+}
+
+func TestCompleteDirectives(t *testing.T) {
+	cases := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"open", "--dir", ""}, ":16\n"},
+		{[]string{"open", "--config", ""}, "yaml\nyml\n:8\n"},
+		{[]string{"open", ""}, "key=\n:6\n"},
+		{[]string{"open", "--depth", ""}, ":4\n"},
+		{[]string{"open", "--depth", "deep", ""}, ":1\n"},
+		{[]string{"--bogus", ""}, ":1\n"},
+		{[]string{"remote", "bogus", ""}, ":1\n"},
+		{[]string{"remote", "a"}, "add\n:4\n"},
+	}
+	for _, c := range cases {
+		var out bytes.Buffer
+		app := &App{
+			Name:                 "tool",
+			Writer:               &out,
+			EnableBashCompletion: true,
+			Commands: []Command{
+				{
+					Name: "open",
+					Flags: []Flag{
+						StringFlag{Name: "dir", Complete: func(c *Context, partial string) []string {
+							c.SetCompletionDirective(CompletionDirectiveFilterDirs)
+							return nil
+						}},
+						StringFlag{Name: "config", Complete: func(c *Context, partial string) []string {
+							c.SetCompletionDirective(CompletionDirectiveFilterFileExt)
+							return []string{"yaml", "yml"}
+						}},
+						IntFlag{Name: "depth"},
+					},
+					BashComplete: func(c *Context) {
+						fmt.Fprintln(c.App.Writer, "key=")
+						c.SetCompletionDirective(CompletionDirectiveNoSpace | CompletionDirectiveNoFileComp)
+					},
+					Action: func(c *Context) error { return nil },
+				},
+				{
+					Name:        "remote",
+					Subcommands: []Command{{Name: "add", Action: func(c *Context) error { return nil }}},
+				},
+			},
+		}
+		if err := app.Run(append([]string{"tool", "__complete"}, c.args...)); err != nil {
+			t.Fatalf("%v: unexpected error: %v", c.args, err)
+		}
+		if out.String() != c.expected {
+			t.Errorf("%v: expected %q, got %q", c.args, c.expected, out.String())
+		}
+	}
+}
+
+func TestCompletionInstall(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	run := func(args ...string) string {
+		var out bytes.Buffer
+		app := &App{Name: "tool", Writer: &out, EnableCompletionCommand: true}
+		if err := app.Run(append([]string{"tool", "completion"}, args...)); err != nil {
+			t.Fatalf("%v: unexpected error: %v", args, err)
+		}
+		return out.String()
+	}
+
+	bashPath := filepath.Join(home, ".local", "share", "bash-completion", "completions", "tool")
+	if out := run("install", "bash"); out != "Installed bash completion to "+bashPath+"\n" {
+		t.Errorf("unexpected output %q", out)
+	}
+	script, err := os.ReadFile(bashPath)
+	if err != nil || string(script) != run("bash") {
+		t.Errorf("expected installed script to match printed script, got error %v", err)
+	}
+	if out := run("install", "bash"); out != "bash completion in "+bashPath+" is up to date\n" {
+		t.Errorf("expected idempotent install, got %q", out)
+	}
+
+	dir := filepath.Join(home, "custom")
+	if out := run("install", "--dir", dir, "fish"); out != "Installed fish completion to "+filepath.Join(dir, "tool.fish")+"\n" {
+		t.Errorf("unexpected output %q", out)
+	}
+
+	run("install", "powershell")
+	run("install", "powershell")
+	profile, err := os.ReadFile(filepath.Join(home, ".config", "powershell", "Microsoft.PowerShell_profile.ps1"))
+	if err != nil {
+		t.Fatalf("expected profile to be written: %v", err)
+	}
+	if strings.Count(string(profile), "tool.ps1") != 1 {
+		t.Errorf("expected profile to source the script once, got %q", profile)
+	}
+}
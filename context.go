@@ -1,11 +1,18 @@
 package cli
 
-import "flag"
+import (
+	"errors"
+	"flag"
+	"reflect"
+	"strings"
+)
 
 type Context struct {
 	App           *App
 	Command       Command
 	shellComplete bool
+	completeWord  string
+	directive     *CompletionDirective
 	flagSet       *flag.FlagSet
 	setFlags      map[string]bool
 	parentContext *Context
@@ -15,14 +22,108 @@ func NewContext(app *App, set *flag.FlagSet, parentCtx *Context) *Context {
 	c := &Context{App: app, flagSet: set, parentContext: parentCtx}
 	if parentCtx != nil {
 		c.shellComplete = parentCtx.shellComplete
+		c.completeWord = parentCtx.completeWord
 	}
 	return c
 }
 
+func (c *Context) Args() Args {
+	ret := args(c.flagSet.Args())
+	return &ret
+}
+
+func (c *Context) NArg() int {
+	return c.Args().Len()
+}
+
 func (c *Context) NumFlags() int {
 	return c.flagSet.NFlag()
 }
 
+func (c *Context) IsSet(name string) bool {
+	if c.setFlags == nil {
+		c.setFlags = make(map[string]bool)
+		c.flagSet.Visit(func(f *flag.Flag) {
+			c.setFlags[f.Name] = true
+		})
+		for _, f := range c.flags() {
+			if flagIsSet(f, c.flagSet) {
+				eachName(f.GetName(), func(name string) {
+					c.setFlags[name] = true
+				})
+			}
+		}
+	}
+	return c.setFlags[name]
+}
+
+func (c *Context) GlobalIsSet(name string) bool {
+	ctx := c
+	if ctx.parentContext != nil {
+		ctx = ctx.parentContext
+	}
+	for ; ctx != nil; ctx = ctx.parentContext {
+		if ctx.IsSet(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Context) flags() []Flag {
+	if c.parentContext != nil {
+		return c.Command.Flags
+	}
+	if c.App != nil {
+		return c.App.Flags
+	}
+	return nil
+}
+
+func (c *Context) Lineage() []*Context {
+	var lineage []*Context
+	for ctx := c; ctx != nil; ctx = ctx.parentContext {
+		lineage = append(lineage, ctx)
+	}
+	return lineage
+}
+
+func (c *Context) CommandPath() string {
+	lineage := c.Lineage()
+	var names []string
+	for i := len(lineage) - 1; i >= 0; i-- {
+		ctx := lineage[i]
+		if ctx.parentContext == nil {
+			if ctx.App != nil {
+				names = append(names, ctx.App.Name)
+			}
+			continue
+		}
+		if ctx.Command.Name != "" {
+			names = append(names, ctx.Command.Name)
+		}
+	}
+	return strings.Join(names, " ")
+}
+
+func (c *Context) FlagNames() []string {
+	var names []string
+	for _, f := range c.flags() {
+		var name string
+		eachName(f.GetName(), func(n string) {
+			if name == "" {
+				name = n
+			}
+		})
+		names = append(names, name)
+	}
+	return names
+}
+
+func (c *Context) GlobalFlagNames() []string {
+	return globalContext(c).FlagNames()
+}
+
 func (c *Context) Set(name, value string) error {
 	c.setFlags = nil
 	return c.flagSet.Set(name, value)
@@ -56,3 +157,49 @@ func lookupGlobalFlagSet(name string, ctx *Context) *flag.FlagSet {
 	}
 	return nil
 }
+
+func normalizeFlags(flags []Flag, set *flag.FlagSet) error {
+	visited := make(map[string]bool)
+	set.Visit(func(f *flag.Flag) {
+		visited[f.Name] = true
+	})
+	for _, f := range flags {
+		parts := strings.Split(f.GetName(), ",")
+		if len(parts) == 1 {
+			continue
+		}
+		var ff *flag.Flag
+		for _, name := range parts {
+			name = strings.Trim(name, " ")
+			if visited[name] {
+				if ff != nil {
+					return errors.New("Cannot use two forms of the same flag: " + name + " " + ff.Name)
+				}
+				ff = set.Lookup(name)
+			}
+		}
+		if ff == nil {
+			continue
+		}
+		for _, name := range parts {
+			name = strings.Trim(name, " ")
+			if !visited[name] {
+				copyFlag(name, ff, set)
+			}
+		}
+	}
+	return nil
+}
+
+func copyFlag(name string, ff *flag.Flag, set *flag.FlagSet) {
+	target := set.Lookup(name)
+	if target == nil || sharesValue(target.Value, ff.Value) {
+		return
+	}
+	_ = set.Set(name, ff.Value.String())
+}
+
+func sharesValue(a, b flag.Value) bool {
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+	return av.Kind() == reflect.Ptr && bv.Kind() == reflect.Ptr && av.Pointer() == bv.Pointer()
+}
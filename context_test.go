@@ -0,0 +1,273 @@
+package cli
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestContextArgs(t *testing.T) {
+	var appArgs, commandArgs []string
+	var first string
+	var tail []string
+	var narg int
+	app := &App{
+		Name:  "tool",
+		Flags: []Flag{BoolFlag{Name: "verbose"}},
+		Commands: []Command{
+			{
+				Name:  "copy",
+				Flags: []Flag{BoolFlag{Name: "force"}},
+				Action: func(c *Context) error {
+					commandArgs = c.Args().Slice()
+					first, tail, narg = c.Args().First(), c.Args().Tail(), c.NArg()
+					return nil
+				},
+			},
+		},
+		Before: func(c *Context) error {
+			appArgs = c.Args().Slice()
+			return nil
+		},
+	}
+	if err := app.Run([]string{"tool", "--verbose", "copy", "--force", "src", "dst"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"copy", "--force", "src", "dst"}; !reflect.DeepEqual(appArgs, expected) {
+		t.Errorf("expected app args %v, got %v", expected, appArgs)
+	}
+	if expected := []string{"src", "dst"}; !reflect.DeepEqual(commandArgs, expected) {
+		t.Errorf("expected command args %v, got %v", expected, commandArgs)
+	}
+	if first != "src" || !reflect.DeepEqual(tail, []string{"dst"}) || narg != 2 {
+		t.Errorf("unexpected first %q, tail %v, narg %d", first, tail, narg)
+	}
+}
+
+func TestArgsEmpty(t *testing.T) {
+	a := args(nil)
+	if a.Present() || a.Len() != 0 || a.First() != "" || a.Get(3) != "" {
+		t.Error("expected empty args")
+	}
+	if tail := a.Tail(); tail == nil || len(tail) != 0 {
+		t.Errorf("expected empty tail, got %#v", tail)
+	}
+}
+
+func TestContextIsSet(t *testing.T) {
+	t.Setenv("TOOL_TOKEN", "secret")
+	results := map[string]bool{}
+	app := &App{
+		Name: "tool",
+		Flags: []Flag{
+			IntFlag{Name: "port, p", Value: 8080},
+			StringFlag{Name: "token", EnvVar: "TOOL_TOKEN"},
+			StringFlag{Name: "region", EnvVar: "TOOL_REGION_UNSET"},
+		},
+		Commands: []Command{
+			{
+				Name:  "serve",
+				Flags: []Flag{BoolFlag{Name: "tls"}},
+				Action: func(c *Context) error {
+					results["tls"] = c.IsSet("tls")
+					results["port"] = c.IsSet("port")
+					results["global port"] = c.GlobalIsSet("port")
+					results["global p"] = c.GlobalIsSet("p")
+					results["global token"] = c.GlobalIsSet("token")
+					results["global region"] = c.GlobalIsSet("region")
+					results["global tls"] = c.GlobalIsSet("tls")
+					return nil
+				},
+			},
+		},
+	}
+	if err := app.Run([]string{"tool", "-p", "8080", "serve", "--tls"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]bool{
+		"tls":           true,
+		"port":          false,
+		"global port":   true,
+		"global p":      true,
+		"global token":  true,
+		"global region": false,
+		"global tls":    false,
+	}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("expected %v, got %v", expected, results)
+	}
+}
+
+func TestContextIsSetAfterSet(t *testing.T) {
+	app := &App{
+		Name:  "tool",
+		Flags: []Flag{StringFlag{Name: "name"}},
+		Action: func(c *Context) error {
+			if c.IsSet("name") {
+				t.Error("expected name not to be set")
+			}
+			if err := c.Set("name", "gopher"); err != nil {
+				return err
+			}
+			if !c.IsSet("name") {
+				t.Error("expected name to be set after Set")
+			}
+			return nil
+		},
+	}
+	if err := app.Run([]string{"tool"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestContextLineageAndCommandPath(t *testing.T) {
+	var path string
+	var depth int
+	var flagNames, globalFlagNames []string
+	app := &App{
+		Name:     "tool",
+		HideHelp: true,
+		Flags:    []Flag{BoolFlag{Name: "verbose, V"}},
+		Commands: []Command{
+			{
+				Name:    "remote",
+				Aliases: []string{"r"},
+				Subcommands: []Command{
+					{
+						Name:     "add",
+						HideHelp: true,
+						Flags:    []Flag{StringFlag{Name: "branch, b"}, BoolFlag{Name: "force"}},
+						Action: func(c *Context) error {
+							path = c.CommandPath()
+							depth = len(c.Lineage())
+							flagNames = c.FlagNames()
+							globalFlagNames = c.GlobalFlagNames()
+							return nil
+						},
+					},
+				},
+			},
+		},
+	}
+	if err := app.Run([]string{"tool", "r", "add"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if path != "tool remote add" || depth != 3 {
+		t.Errorf("unexpected path %q with lineage depth %d", path, depth)
+	}
+	if expected := []string{"branch", "force"}; !reflect.DeepEqual(flagNames, expected) {
+		t.Errorf("expected flag names %v, got %v", expected, flagNames)
+	}
+	if expected := []string{"verbose", "version", "cli-spec"}; !reflect.DeepEqual(globalFlagNames, expected) {
+		t.Errorf("expected global flag names %v, got %v", expected, globalFlagNames)
+	}
+}
+
+func TestCommandPathInErrors(t *testing.T) {
+	var buf bytes.Buffer
+	oldWriter := ErrWriter
+	defer func() { ErrWriter = oldWriter }()
+	ErrWriter = &buf
+
+	app := &App{
+		Name:           "tool",
+		ExitErrHandler: func(c *Context, err error) {},
+		Commands: []Command{
+			{
+				Name: "remote",
+				Subcommands: []Command{
+					{
+						Name:   "add",
+						Flags:  []Flag{IntFlag{Name: "depth"}},
+						Action: func(c *Context) error { return nil },
+					},
+				},
+			},
+		},
+	}
+	err := app.Run([]string{"tool", "remote", "addd"})
+	expected := "'addd' is not a tool remote command. did you mean 'add'?"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+
+	err = app.Run([]string{"tool", "remote", "add", "--depth", "deep"})
+	if usageErr, ok := err.(*UsageError); !ok || usageErr.Command != "tool remote add" {
+		t.Fatalf("expected usage error for tool remote add, got %#v", err)
+	}
+	for _, expected := range []string{
+		"Usage: tool remote add [options] [arguments...]",
+		"Run 'tool help remote add' for usage.",
+	} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("expected %q in %q", expected, buf.String())
+		}
+	}
+}
+
+type modeValue struct {
+	mode string
+}
+
+func (m *modeValue) Set(value string) error {
+	m.mode = value
+	return nil
+}
+
+func (m *modeValue) String() string {
+	return m.mode
+}
+
+func TestContextAccessorTypeMismatch(t *testing.T) {
+	results := map[string]interface{}{}
+	app := &App{
+		Name: "tool",
+		Flags: []Flag{
+			GenericFlag{Name: "mode", Value: &modeValue{}},
+			IntFlag{Name: "port"},
+			DurationFlag{Name: "timeout"},
+			Int64Flag{Name: "big"},
+			StringFlag{Name: "ratio"},
+			BoolFlag{Name: "verbose"},
+		},
+		Commands: []Command{
+			{
+				Name: "serve",
+				Action: func(c *Context) error {
+					results["global port"] = c.GlobalString("port")
+					results["global big"] = c.GlobalUint("big")
+					return nil
+				},
+			},
+		},
+		Before: func(c *Context) error {
+			results["mode"] = c.String("mode")
+			results["port"] = c.String("port")
+			results["timeout"] = c.String("timeout")
+			results["big"] = c.Int("big")
+			results["ratio"] = c.Float64("ratio")
+			results["verbose"] = c.Int("verbose")
+			results["exact"] = Value[int](c, "big")
+			return nil
+		},
+	}
+	args := []string{"tool", "--mode", "fast", "--port", "80", "--timeout", "1h", "--big", "5", "--ratio", "0.5", "--verbose", "serve"}
+	if err := app.Run(args); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]interface{}{
+		"mode":        "fast",
+		"port":        "80",
+		"timeout":     "1h",
+		"big":         5,
+		"ratio":       0.5,
+		"verbose":     0,
+		"exact":       0,
+		"global port": "80",
+		"global big":  uint(5),
+	}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("expected %v, got %v", expected, results)
+	}
+}
@@ -0,0 +1,77 @@
+package cli
+
+import "strings"
+
+type flagDoc struct {
+	Names       []string
+	Placeholder string
+	Usage       string
+	Default     string
+	EnvVars     []string
+	FilePaths   []string
+	TakesValue  bool
+	TakesFile   bool
+	Required    bool
+}
+
+func newFlagDoc(f Flag) flagDoc {
+	doc := flagDoc{TakesFile: flagTakesFile(f)}
+	eachName(f.GetName(), func(name string) {
+		doc.Names = append(doc.Names, name)
+	})
+	if df, ok := f.(DocGenerationFlag); ok {
+		doc.Placeholder, doc.Usage = unquoteUsage(df.GetUsage())
+		doc.TakesValue = df.TakesValue()
+		if doc.TakesValue {
+			if doc.Placeholder == "" {
+				doc.Placeholder = defaultPlaceholder
+			}
+			doc.Default = flagDefault(df)
+		}
+	}
+	if rf, ok := f.(RequiredFlag); ok {
+		doc.Required = rf.IsRequired()
+	}
+	fv := flagValue(f)
+	if field := fv.FieldByName("EnvVar"); field.IsValid() {
+		doc.EnvVars = splitList(field.String())
+	}
+	if field := fv.FieldByName("FilePath"); field.IsValid() {
+		doc.FilePaths = splitList(field.String())
+	}
+	return doc
+}
+
+func flagDefault(f DocGenerationFlag) string {
+	if val := flagValue(f).FieldByName("Value"); val.IsValid() && val.IsZero() {
+		return ""
+	}
+	return f.GetValue()
+}
+
+func (d flagDoc) prefixedNames() []string {
+	names := make([]string, len(d.Names))
+	for i, name := range d.Names {
+		names[i] = prefixFor(name) + name
+	}
+	return names
+}
+
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newDocsTestApp() *App {
+	app := newHelpTestApp(nil)
+	app.Version = "v1.0.0"
+	app.Description = "Manages things.\n.dotted line"
+	app.Flags = append(app.Flags, StringFlag{
+		Name:     "config, c",
+		Usage:    "load configuration from `FILE`",
+		EnvVar:   "TOOL_CONFIG",
+		FilePath: "/etc/tool/config",
+	})
+	app.Commands[2].Aliases = []string{"rm"}
+	app.Commands[2].Subcommands[0].Flags = append(app.Commands[2].Subcommands[0].Flags,
+		StringFlag{Name: "token", Usage: "API token", EnvVar: "TOOL_TOKEN", Required: true})
+	return app
+}
+
+func TestToMan(t *testing.T) {
+	man, err := newDocsTestApp().ToMan()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, expected := range []string{
+		".TH \"TOOL\" 1 \"\" \"tool v1.0.0\" \"User Commands\"\n",
+		".SH NAME\ntool \\- does things\n",
+		".SH DESCRIPTION\nManages things.\n\\&.dotted line\n",
+		".SH \"GLOBAL OPTIONS\"\n.TP\n\\fB\\-\\-verbose\\fR\nbe loud\n",
+		".TP\n\\fB\\-\\-config\\fR, \\fB\\-c\\fR \\fIFILE\\fR\nload configuration from FILE\n",
+		".SS \"remote add\"\nadd a remote\n",
+		".TP\n\\fB\\-\\-depth\\fR \\fIvalue\\fR\nclone depth (default: 1)\n",
+		"\\fB\\-\\-token\\fR \\fIvalue\\fR\nAPI token (required)\n",
+		".SH ENVIRONMENT\n.TP\n.B TOOL_CONFIG\nSets \\-\\-config, \\-c: load configuration from FILE\n.TP\n.B TOOL_TOKEN\n",
+		".SH FILES\n.TP\n.I /etc/tool/config\n",
+	} {
+		if !strings.Contains(man, expected) {
+			t.Errorf("expected man page to contain %q, got:\n%s", expected, man)
+		}
+	}
+	if strings.Contains(man, "secret") {
+		t.Errorf("expected hidden command to be omitted, got:\n%s", man)
+	}
+}
+
+func TestToMarkdown(t *testing.T) {
+	md, err := newDocsTestApp().ToMarkdown()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, expected := range []string{
+		"# tool\n\ndoes things\n\n**Usage:**\n\n```\ntool [global options] command [command options] [arguments...]\n```\n",
+		"| [remote](#tool-remote) | `rm` | manage remotes |\n",
+		"| `--config`, `-c` | `FILE` |  | `TOOL_CONFIG` | `/etc/tool/config` | no | load configuration from FILE |\n",
+		"\n## tool remote add\n\nadd a remote\n\nParent: [tool remote](#tool-remote)\n",
+		"| `--depth` | `value` | `1` |  |  | no | clone depth |\n",
+		"| `--token` | `value` |  | `TOOL_TOKEN` |  | yes | API token |\n",
+	} {
+		if !strings.Contains(md, expected) {
+			t.Errorf("expected markdown to contain %q, got:\n%s", expected, md)
+		}
+	}
+	if strings.Contains(md, "\n\n\n") || strings.Contains(md, "secret") {
+		t.Errorf("unexpected markdown:\n%s", md)
+	}
+	again, _ := newDocsTestApp().ToMarkdown()
+	if again != md {
+		t.Error("expected markdown output to be deterministic")
+	}
+}
+
+func TestToMarkdownFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := newDocsTestApp().ToMarkdownFiles(dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	page, err := os.ReadFile(filepath.Join(dir, "tool_remote_add.md"))
+	if err != nil {
+		t.Fatalf("expected a page per command: %v", err)
+	}
+	if !strings.HasPrefix(string(page), "# tool remote add\n\nadd a remote\n\nParent: [tool remote](tool_remote.md)\n") {
+		t.Errorf("unexpected page:\n%s", page)
+	}
+	root, err := os.ReadFile(filepath.Join(dir, "tool.md"))
+	if err != nil || !strings.Contains(string(root), "| [remote](tool_remote.md) |") {
+		t.Errorf("expected root page to link to command pages, got %v:\n%s", err, root)
+	}
+}
+
+func TestSpec(t *testing.T) {
+	app := newDocsTestApp()
+	app.Commands[2].Deprecated = "use 'tool origin' instead"
+	spec := app.Spec()
+	if spec.Name != "tool" || spec.Version != "v1.0.0" {
+		t.Errorf("unexpected spec header %q %q", spec.Name, spec.Version)
+	}
+	var paths []string
+	for _, command := range spec.Commands {
+		paths = append(paths, command.Path)
+	}
+	expectedPaths := []string{"status", "secret", "remote", "remote add", "help"}
+	if !reflect.DeepEqual(paths, expectedPaths) {
+		t.Errorf("expected paths %v, got %v", expectedPaths, paths)
+	}
+	if !spec.Commands[1].Hidden || spec.Commands[2].Deprecated == "" || spec.Commands[2].Category != "setup" {
+		t.Errorf("unexpected command metadata %#v", spec.Commands[1:3])
+	}
+	expectedFlag := FlagSpec{
+		Name:       "token",
+		Names:      []string{"token"},
+		Type:       "StringFlag",
+		TakesValue: true,
+		EnvVars:    []string{"TOOL_TOKEN"},
+		Required:   true,
+		Usage:      "API token",
+	}
+	if flag := spec.Commands[3].Flags[1]; !reflect.DeepEqual(flag, expectedFlag) {
+		t.Errorf("expected %#v, got %#v", expectedFlag, flag)
+	}
+
+	var out bytes.Buffer
+	app.Writer = &out
+	if err := app.Run([]string{"tool", "--cli-spec"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded Spec
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, spec) {
+		t.Errorf("expected printed spec to round trip, got %#v", decoded)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -30,6 +32,74 @@ func (m MultiError) Error() string {
 	return strings.Join(errs, "\n")
 }
 
+type UsageError struct {
+	Command string
+	Flag    string
+	Value   string
+	Err     error
+}
+
+var usageErrorPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`^flag provided but not defined: -(?P<flag>.+)$`),
+	regexp.MustCompile(`^invalid value (?P<value>".*") for flag -(?P<flag>[^:]+): `),
+	regexp.MustCompile(`^invalid boolean value (?P<value>".*") for -(?P<flag>[^:]+): `),
+	regexp.MustCompile(`^invalid boolean flag (?P<flag>[^:]+): `),
+	regexp.MustCompile(`^flag needs an argument: -(?P<flag>.+)$`),
+	regexp.MustCompile(`^bad flag syntax: (?P<value>.*)$`),
+}
+
+func newUsageError(command string, err error) *UsageError {
+	usageErr := &UsageError{Command: command, Err: err}
+	msg := err.Error()
+	for _, pattern := range usageErrorPatterns {
+		match := pattern.FindStringSubmatch(msg)
+		if match == nil {
+			continue
+		}
+		for i, name := range pattern.SubexpNames() {
+			switch name {
+			case "flag":
+				usageErr.Flag = match[i]
+			case "value":
+				usageErr.Value = match[i]
+				if unquoted, err := strconv.Unquote(match[i]); err == nil {
+					usageErr.Value = unquoted
+				}
+			}
+		}
+		break
+	}
+	return usageErr
+}
+
+func (ue *UsageError) Error() string {
+	return ue.Err.Error()
+}
+
+func (ue *UsageError) Unwrap() error {
+	return ue.Err
+}
+
+func (ue *UsageError) ExitCode() int {
+	return 2
+}
+
+type requiredFlagsErr struct {
+	commandPath string
+	flagNames   []string
+}
+
+func (e requiredFlagsErr) Error() string {
+	msg := fmt.Sprintf("Required flags %q not set", strings.Join(e.flagNames, ", "))
+	if len(e.flagNames) == 1 {
+		msg = fmt.Sprintf("Required flag %q not set", e.flagNames[0])
+	}
+	if e.commandPath == "" {
+		return msg
+	}
+	return fmt.Sprintf("%s for %q", msg, e.commandPath)
+}
+
 type ErrorFormatter interface {
 	Format(s fmt.State, verb rune)
 }
@@ -0,0 +1,38 @@
+package cli
+
+var FishCompletionTemplate = `# {{.Name}} fish shell completion
+
+function __{{.FuncName}}_complete --description 'Ask {{.Name}} for completion candidates'
+    set -l args (commandline -opc)
+    set -l out (command $args[1] {{.Command}} $args[2..-1] (commandline -ct) 2>/dev/null)
+    or return
+    set -l directive (string replace -r '^:' '' -- $out[-1])
+    set -e out[-1]
+
+    if test (math "bitand($directive, {{.Error}})") -ne 0
+        return
+    end
+    if test (math "bitand($directive, {{.FilterFileExt}})") -ne 0
+        set -l current (commandline -ct)
+        for ext in $out
+            __fish_complete_suffix $current .$ext
+        end
+        return
+    end
+    if test (math "bitand($directive, {{.FilterDirs}})") -ne 0
+        __fish_complete_directories (commandline -ct)
+        return
+    end
+
+    printf '%s\n' $out
+    if test (count $out) -eq 0; and test (math "bitand($directive, {{.NoFileComp}})") -eq 0
+        __fish_complete_path (commandline -ct)
+    end
+end
+
+complete -c {{.Name}} -f -a '(__{{.FuncName}}_complete)'
+`
+
+func (a *App) ToFishCompletion() (string, error) {
+	return a.executeCompletionTemplate("fish", FishCompletionTemplate)
+}
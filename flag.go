@@ -6,7 +6,6 @@ import (
 	"io/ioutil"
 	"reflect"
 	"runtime"
-	"strconv"
 	"strings"
 	"syscall"
 )
@@ -18,16 +17,22 @@ var BashCompletionFlag Flag = BoolFlag{
 	Hidden: true,
 }
 
-var VersionFlag Flag = BoolFlag{
+var VersionFlag Flag = versionFlag{BoolFlag{
 	Name:  "version, v",
-	Usage: "print the version",
-}
+	Usage: "print the version (add --json for JSON output)",
+}}
 
 var HelpFlag Flag = BoolFlag{
 	Name:  "help, h",
 	Usage: "show help",
 }
 
+var CLISpecFlag Flag = BoolFlag{
+	Name:   "cli-spec",
+	Usage:  "print a JSON description of the command line interface",
+	Hidden: true,
+}
+
 var FlagStringer FlagStringFunc = stringifyFlag
 
 var FlagNamePrefixer FlagNamePrefixFunc = prefixedNames
@@ -88,6 +93,54 @@ func flagSet(name string, flags []Flag) (*flag.FlagSet, error) {
 	return set, nil
 }
 
+func checkRequiredFlags(flags []Flag, ctx *Context) error {
+	var missing []string
+	for _, f := range flags {
+		rf, ok := f.(RequiredFlag)
+		if !ok || !rf.IsRequired() {
+			continue
+		}
+		var name string
+		eachName(f.GetName(), func(n string) {
+			if name == "" {
+				name = n
+			}
+		})
+		if ctx.IsSet(name) {
+			continue
+		}
+		missing = append(missing, name)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return NewMultiError(requiredFlagsErr{commandPath: ctx.CommandPath(), flagNames: missing})
+}
+
+func flagIsSet(f Flag, set *flag.FlagSet) bool {
+	visited := false
+	set.Visit(func(ff *flag.Flag) {
+		eachName(f.GetName(), func(name string) {
+			if ff.Name == name {
+				visited = true
+			}
+		})
+	})
+	if visited {
+		return true
+	}
+	fv := flagValue(f)
+	var envVar, filePath string
+	if field := fv.FieldByName("EnvVar"); field.IsValid() {
+		envVar = field.String()
+	}
+	if field := fv.FieldByName("FilePath"); field.IsValid() {
+		filePath = field.String()
+	}
+	_, ok := flagFromFileEnv(filePath, envVar)
+	return ok
+}
+
 func eachName(longName string, fn func(string)) {
 	parts := strings.Split(longName, ",")
 	for _, name := range parts {
@@ -99,14 +152,30 @@ func eachName(longName string, fn func(string)) {
 func visibleFlags(fl []Flag) []Flag {
 	var visible []Flag
 	for _, f := range fl {
-		field := flagValue(f).FieldByName("Hidden")
-		if !field.IsValid() || !field.Bool() {
+		if !flagHidden(f) {
 			visible = append(visible, f)
 		}
 	}
 	return visible
 }
 
+func flagHidden(f Flag) bool {
+	field := flagValue(f).FieldByName("Hidden")
+	return field.IsValid() && field.Bool()
+}
+
+func flagTypeName(f Flag) string {
+	if tf, ok := f.(interface{ typeName() string }); ok {
+		return tf.typeName()
+	}
+	return flagValue(f).Type().Name()
+}
+
+func flagTakesFile(f Flag) bool {
+	field := flagValue(f).FieldByName("TakesFile")
+	return field.IsValid() && field.Bool()
+}
+
 func prefixFor(name string) (prefix string) {
 	if len(name) == 1 {
 		prefix = "-"
@@ -183,41 +252,31 @@ func flagValue(f Flag) reflect.Value {
 
 func stringifyFlag(f Flag) string {
 	fv := flagValue(f)
-	switch f.(type) {
-	case IntSliceFlag:
-		return FlagFileHinter(
-			fv.FieldByName("FilePath").String(),
-			FlagEnvHinter(
-				fv.FieldByName("EnvVar").String(),
-				stringifyIntSliceFlag(f.(IntSliceFlag)),
-			),
-		)
-	case Int64SliceFlag:
-		return FlagFileHinter(
-			fv.FieldByName("FilePath").String(),
-			FlagEnvHinter(
-				fv.FieldByName("EnvVar").String(),
-				stringifyInt64SliceFlag(f.(Int64SliceFlag)),
-			),
-		)
-	case StringSliceFlag:
-		return FlagFileHinter(
-			fv.FieldByName("FilePath").String(),
-			FlagEnvHinter(
-				fv.FieldByName("EnvVar").String(),
-				stringifyStringSliceFlag(f.(StringSliceFlag)),
-			),
-		)
+	if sf, ok := f.(interface{ sliceDefaults() ([]string, bool) }); ok {
+		if defaultVals, isSlice := sf.sliceDefaults(); isSlice {
+			return FlagFileHinter(
+				fv.FieldByName("FilePath").String(),
+				FlagEnvHinter(
+					fv.FieldByName("EnvVar").String(),
+					stringifySliceFlag(fv.FieldByName("Usage").String(), fv.FieldByName("Name").String(), defaultVals),
+				),
+			)
+		}
 	}
 	placeholder, usage := unquoteUsage(fv.FieldByName("Usage").String())
 	needsPlaceholder := false
 	defaultValueString := ""
 	if val := fv.FieldByName("Value"); val.IsValid() {
 		needsPlaceholder = true
-		defaultValueString = fmt.Sprintf(" (default: %v)", val.Interface())
-
-		if val.Kind() == reflect.String && val.String() != "" {
-			defaultValueString = fmt.Sprintf(" (default: %q)", val.String())
+		if dt, ok := f.(interface{ defaultText() string }); ok {
+			if text := dt.defaultText(); text != "" {
+				defaultValueString = fmt.Sprintf(" (default: %s)", text)
+			}
+		} else {
+			defaultValueString = fmt.Sprintf(" (default: %v)", val.Interface())
+			if val.Kind() == reflect.String && val.String() != "" {
+				defaultValueString = fmt.Sprintf(" (default: %q)", val.String())
+			}
 		}
 	}
 	if defaultValueString == " (default: )" {
@@ -236,38 +295,6 @@ func stringifyFlag(f Flag) string {
 	)
 }
 
-func stringifyIntSliceFlag(f IntSliceFlag) string {
-	var defaultVals []string
-	if f.Value != nil && len(f.Value.Value()) > 0 {
-		for _, i := range f.Value.Value() {
-			defaultVals = append(defaultVals, strconv.Itoa(i))
-		}
-	}
-	return stringifySliceFlag(f.Usage, f.Name, defaultVals)
-}
-
-func stringifyInt64SliceFlag(f Int64SliceFlag) string {
-	var defaultVals []string
-	if f.Value != nil && len(f.Value.Value()) > 0 {
-		for _, i := range f.Value.Value() {
-			defaultVals = append(defaultVals, strconv.FormatInt(i, 10))
-		}
-	}
-	return stringifySliceFlag(f.Usage, f.Name, defaultVals)
-}
-
-func stringifyStringSliceFlag(f StringSliceFlag) string {
-	var defaultVals []string
-	if f.Value != nil && len(f.Value.Value()) > 0 {
-		for _, s := range f.Value.Value() {
-			if len(s) > 0 {
-				defaultVals = append(defaultVals, strconv.Quote(s))
-			}
-		}
-	}
-	return stringifySliceFlag(f.Usage, f.Name, defaultVals)
-}
-
 func stringifySliceFlag(usage, name string, defaultVals []string) string {
 	placeholder, usage := unquoteUsage(usage)
 	if placeholder == "" {
@@ -0,0 +1,308 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type ValueCodec[T any] interface {
+	Parse(value string) (T, error)
+	Format(value T) string
+}
+
+type FlagBase[T any, C ValueCodec[T]] struct {
+	Name        string
+	Usage       string
+	EnvVar      string
+	FilePath    string
+	Required    bool
+	Hidden      bool
+	TakesFile   bool
+	Complete    FlagCompleteFunc
+	Value       T
+	Destination *T
+	Codec       C
+}
+
+func (f FlagBase[T, C]) String() string {
+	return FlagStringer(f)
+}
+
+func (f FlagBase[T, C]) GetName() string {
+	return f.Name
+}
+
+func (f FlagBase[T, C]) IsRequired() bool {
+	return f.Required
+}
+
+func (f FlagBase[T, C]) TakesValue() bool {
+	return true
+}
+
+func (f FlagBase[T, C]) GetUsage() string {
+	return f.Usage
+}
+
+func (f FlagBase[T, C]) GetValue() string {
+	return f.Codec.Format(f.Value)
+}
+
+func (f FlagBase[T, C]) defaultText() string {
+	text := f.GetValue()
+	if text != "" && reflect.TypeOf(&f.Value).Elem().Kind() == reflect.String {
+		return strconv.Quote(text)
+	}
+	return text
+}
+
+func (f FlagBase[T, C]) Apply(set *flag.FlagSet) {
+	_ = f.ApplyWithError(set)
+}
+
+func (f FlagBase[T, C]) ApplyWithError(set *flag.FlagSet) error {
+	dest := f.Destination
+	if dest == nil {
+		dest = new(T)
+	}
+	*dest = f.Value
+	val := &baseValue[T, C]{dest: dest, codec: f.Codec}
+	if envVal, ok := flagFromFileEnv(f.FilePath, f.EnvVar); ok {
+		if err := val.setEnv(envVal); err != nil {
+			return fmt.Errorf("could not parse %s as %s for flag %s: %s", envVal, codecDescription[C](), f.Name, err)
+		}
+	}
+	eachName(f.Name, func(name string) {
+		set.Var(val, name, f.Usage)
+	})
+	return nil
+}
+
+func (f FlagBase[T, C]) typeName() string {
+	return codecTypeName[C]()
+}
+
+func (f FlagBase[T, C]) sliceDefaults() ([]string, bool) {
+	d, ok := any(f.Codec).(defaultsFormatter[T])
+	if !ok {
+		return nil, false
+	}
+	return d.formatDefaults(f.Value), true
+}
+
+type FlagTypeNamer interface {
+	FlagTypeName() string
+}
+
+func codecTypeName[C any]() string {
+	var codec C
+	if n, ok := any(codec).(FlagTypeNamer); ok {
+		return n.FlagTypeName()
+	}
+	t := reflect.TypeOf(&codec).Elem()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	name := t.Name()
+	if i := strings.Index(name, "["); i >= 0 {
+		name = name[:i]
+	}
+	name = strings.TrimSuffix(name, "Codec")
+	if name == "" {
+		return "Flag"
+	}
+	return strings.ToUpper(name[:1]) + name[1:] + "Flag"
+}
+
+type codecKind interface {
+	kind() string
+}
+
+type valueAppender[T any] interface {
+	Append(values, value T) T
+}
+
+type defaultsFormatter[T any] interface {
+	formatDefaults(values T) []string
+}
+
+func codecDescription[C any]() string {
+	var codec C
+	if k, ok := any(codec).(codecKind); ok {
+		return k.kind() + " value"
+	}
+	return "value"
+}
+
+type baseValue[T any, C ValueCodec[T]] struct {
+	dest  *T
+	codec C
+	set   bool
+}
+
+func (v *baseValue[T, C]) Set(value string) error {
+	parsed, err := v.codec.Parse(value)
+	if err != nil {
+		return err
+	}
+	if a, ok := any(v.codec).(valueAppender[T]); ok && v.set {
+		parsed = a.Append(*v.dest, parsed)
+	}
+	*v.dest = parsed
+	v.set = true
+	return nil
+}
+
+func (v *baseValue[T, C]) setEnv(envVal string) error {
+	if _, ok := any(v.codec).(valueAppender[T]); !ok {
+		return v.Set(envVal)
+	}
+	for _, s := range strings.Split(envVal, ",") {
+		if err := v.Set(strings.TrimSpace(s)); err != nil {
+			return err
+		}
+	}
+	v.set = false
+	return nil
+}
+
+func (v *baseValue[T, C]) String() string {
+	if v == nil || v.dest == nil {
+		var codec C
+		var zero T
+		return codec.Format(zero)
+	}
+	return v.codec.Format(*v.dest)
+}
+
+func (v *baseValue[T, C]) Get() interface{} {
+	if g, ok := any(*v.dest).(flag.Getter); ok {
+		return g.Get()
+	}
+	return *v.dest
+}
+
+func Value[T any](ctx *Context, name string) T {
+	return lookupValue[T](name, ctx.flagSet, nil)
+}
+
+func globalValue[T any](ctx *Context, name string, parse func(string) (T, error)) T {
+	if fs := lookupGlobalFlagSet(name, ctx); fs != nil {
+		return lookupValue(name, fs, parse)
+	}
+	var zero T
+	return zero
+}
+
+func lookupValue[T any](name string, set *flag.FlagSet, parse func(string) (T, error)) T {
+	var zero T
+	f := set.Lookup(name)
+	if f == nil {
+		return zero
+	}
+	if g, ok := f.Value.(flag.Getter); ok {
+		if v, ok := g.Get().(T); ok {
+			return v
+		}
+	}
+	if parse == nil {
+		return zero
+	}
+	v, err := parse(f.Value.String())
+	if err != nil {
+		return zero
+	}
+	return v
+}
+
+type Slice[E any, C ValueCodec[E]] []E
+
+func (s *Slice[E, C]) Set(value string) error {
+	var codec C
+	v, err := codec.Parse(value)
+	if err != nil {
+		return err
+	}
+	*s = append(*s, v)
+	return nil
+}
+
+func (s *Slice[E, C]) String() string {
+	var codec C
+	formatted := make([]string, len(s.Value()))
+	for i, v := range s.Value() {
+		formatted[i] = codec.Format(v)
+	}
+	return strings.Join(formatted, ",")
+}
+
+func (s *Slice[E, C]) Value() []E {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+func (s *Slice[E, C]) Get() interface{} {
+	return s.Value()
+}
+
+type SliceCodec[E any, C ValueCodec[E]] struct{}
+
+func (SliceCodec[E, C]) Parse(value string) (*Slice[E, C], error) {
+	var codec C
+	v, err := codec.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	return &Slice[E, C]{v}, nil
+}
+
+func (SliceCodec[E, C]) Format(values *Slice[E, C]) string {
+	return values.String()
+}
+
+func (SliceCodec[E, C]) Append(values, value *Slice[E, C]) *Slice[E, C] {
+	ret := append(Slice[E, C]{}, values.Value()...)
+	ret = append(ret, value.Value()...)
+	return &ret
+}
+
+func (SliceCodec[E, C]) formatDefaults(values *Slice[E, C]) []string {
+	var codec C
+	var formatted []string
+	for _, v := range values.Value() {
+		if s, ok := any(v).(string); ok {
+			if s != "" {
+				formatted = append(formatted, strconv.Quote(s))
+			}
+			continue
+		}
+		formatted = append(formatted, codec.Format(v))
+	}
+	return formatted
+}
+
+func (SliceCodec[E, C]) FlagTypeName() string {
+	return strings.TrimSuffix(codecTypeName[C](), "Flag") + "SliceFlag"
+}
+
+func (SliceCodec[E, C]) kind() string {
+	var codec C
+	if k, ok := any(codec).(codecKind); ok {
+		return k.kind() + " slice"
+	}
+	return "slice"
+}
+
+func numError(err error) error {
+	var ne *strconv.NumError
+	if errors.As(err, &ne) && ne.Err == strconv.ErrRange {
+		return errors.New("value out of range")
+	}
+	return errors.New("parse error")
+}
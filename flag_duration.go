@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+)
+
+var durationDayWeek = regexp.MustCompile(`([0-9]*\.?[0-9]+)([dw])`)
+
+type DurationFlag = FlagBase[time.Duration, DurationCodec]
+
+type DurationCodec struct{}
+
+func (DurationCodec) Parse(value string) (time.Duration, error) {
+	return parseDuration(strings.TrimSpace(value))
+}
+
+func (DurationCodec) Format(value time.Duration) string {
+	return formatDuration(value)
+}
+
+func (DurationCodec) kind() string {
+	return "duration"
+}
+
+func parseDuration(s string) (time.Duration, error) {
+	var convErr error
+	expanded := durationDayWeek.ReplaceAllStringFunc(s, func(m string) string {
+		parts := durationDayWeek.FindStringSubmatch(m)
+		n, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			convErr = err
+			return m
+		}
+		hours := n * 24
+		if parts[2] == "w" {
+			hours *= 7
+		}
+		return strconv.FormatFloat(hours, 'f', -1, 64) + "h"
+	})
+	if convErr != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	d, err := time.ParseDuration(expanded)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
+
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return "0s"
+	}
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	if d%week == 0 {
+		return sign + strconv.FormatInt(int64(d/week), 10) + "w"
+	}
+	var b strings.Builder
+	b.WriteString(sign)
+	for _, unit := range []struct {
+		size   time.Duration
+		suffix string
+	}{{day, "d"}, {time.Hour, "h"}, {time.Minute, "m"}} {
+		if d >= unit.size {
+			b.WriteString(strconv.FormatInt(int64(d/unit.size), 10) + unit.suffix)
+			d %= unit.size
+		}
+	}
+	if d > 0 {
+		b.WriteString(d.String())
+	}
+	return b.String()
+}
+
+func (c *Context) Duration(name string) time.Duration {
+	return lookupValue(name, c.flagSet, DurationCodec{}.Parse)
+}
+
+func (c *Context) GlobalDuration(name string) time.Duration {
+	return globalValue(c, name, DurationCodec{}.Parse)
+}
@@ -0,0 +1,13 @@
+package cli
+
+type Float64Slice = Slice[float64, Float64Codec]
+
+type Float64SliceFlag = FlagBase[*Float64Slice, SliceCodec[float64, Float64Codec]]
+
+func (c *Context) Float64Slice(name string) []float64 {
+	return Value[[]float64](c, name)
+}
+
+func (c *Context) GlobalFloat64Slice(name string) []float64 {
+	return globalValue[[]float64](c, name, nil)
+}
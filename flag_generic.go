@@ -18,6 +18,7 @@ type GenericFlag struct {
 	Required  bool
 	Hidden    bool
 	TakesFile bool
+	Complete  FlagCompleteFunc
 	Value     Generic
 }
 
@@ -0,0 +1,421 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	for input, expected := range map[string]time.Duration{
+		"90s":    90 * time.Second,
+		"2d":     48 * time.Hour,
+		"1w":     7 * 24 * time.Hour,
+		"1w2d3h": (9*24 + 3) * time.Hour,
+		"1.5d":   36 * time.Hour,
+		"-1d":    -24 * time.Hour,
+	} {
+		d, err := parseDuration(input)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", input, err)
+			continue
+		}
+		if d != expected {
+			t.Errorf("%s: expected %v, got %v", input, expected, d)
+		}
+	}
+	if _, err := parseDuration("2 days"); err == nil {
+		t.Error("expected error for invalid duration")
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	for d, expected := range map[time.Duration]string{
+		0:                                    "0s",
+		30 * time.Second:                     "30s",
+		90 * time.Minute:                     "1h30m",
+		2 * time.Hour:                        "2h",
+		48 * time.Hour:                       "2d",
+		14 * 24 * time.Hour:                  "2w",
+		36 * time.Hour:                       "1d12h",
+		25*time.Hour + 30*time.Minute:        "1d1h30m",
+		time.Hour + 30*time.Second:           "1h30s",
+		8 * 24 * time.Hour:                   "8d",
+		26*time.Hour + 1500*time.Millisecond: "1d2h1.5s",
+		90 * time.Second:                     "1m30s",
+		-24 * time.Hour:                      "-1d",
+		1500 * time.Millisecond:              "1.5s",
+	} {
+		if got := formatDuration(d); got != expected {
+			t.Errorf("%v: expected %q, got %q", d, expected, got)
+		}
+		if parsed, err := parseDuration(expected); err != nil || parsed != d {
+			t.Errorf("%q: expected to parse back to %v, got %v (%v)", expected, d, parsed, err)
+		}
+	}
+}
+
+func TestDurationFlag(t *testing.T) {
+	t.Setenv("TOOL_RETENTION", "1w")
+	var timeout time.Duration
+	var retention, global time.Duration
+	app := &App{
+		Name: "tool",
+		Flags: []Flag{
+			DurationFlag{Name: "timeout, t", Value: 30 * time.Second, Destination: &timeout},
+		},
+		Commands: []Command{
+			{
+				Name:  "prune",
+				Flags: []Flag{DurationFlag{Name: "retention", EnvVar: "TOOL_RETENTION"}},
+				Action: func(c *Context) error {
+					retention = c.Duration("retention")
+					global = c.GlobalDuration("timeout")
+					return nil
+				},
+			},
+		},
+	}
+	if err := app.Run([]string{"tool", "-t", "2d", "prune"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if timeout != 48*time.Hour || global != 48*time.Hour {
+		t.Errorf("expected timeout of 2d, got destination %v and global %v", timeout, global)
+	}
+	if retention != 7*24*time.Hour {
+		t.Errorf("expected retention of 1w from env, got %v", retention)
+	}
+}
+
+func TestDurationFlagHelp(t *testing.T) {
+	f := DurationFlag{Name: "retention", Usage: "keep backups for `PERIOD`", Value: 14 * 24 * time.Hour}
+	expected := "--retention PERIOD\tkeep backups for PERIOD (default: 2w)"
+	if got := f.String(); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	set.SetOutput(io.Discard)
+	if err := f.ApplyWithError(set); err != nil {
+		t.Fatal(err)
+	}
+	if err := set.Parse([]string{"--retention", "soon"}); err == nil || !strings.Contains(err.Error(), `invalid duration "soon"`) {
+		t.Errorf("expected invalid duration error, got %v", err)
+	}
+}
+
+func TestTimestampFlag(t *testing.T) {
+	oldNow := timestampNow
+	defer func() { timestampNow = oldNow }()
+	timestampNow = func() time.Time { return time.Date(2024, 3, 10, 15, 30, 0, 0, time.UTC) }
+	t.Setenv("TOOL_UNTIL", "2024-03-09")
+
+	var since, until, dest time.Time
+	var unset *time.Time
+	app := &App{
+		Name: "tool",
+		Flags: []Flag{
+			TimestampFlag{Name: "since, s", Location: time.UTC},
+			TimestampFlag{Name: "until", EnvVar: "TOOL_UNTIL", Layout: "2006-01-02", Location: time.UTC},
+			TimestampFlag{Name: "at", Layouts: []string{"02/01/2006 15:04"}, Location: time.UTC, Destination: &dest},
+			TimestampFlag{Name: "before"},
+		},
+		Action: func(c *Context) error {
+			since, until = *c.Timestamp("since"), *c.Timestamp("until")
+			unset = c.Timestamp("before")
+			return nil
+		},
+	}
+	if err := app.Run([]string{"tool", "-s", "now-2h", "--at", "01/02/2024 08:00"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := time.Date(2024, 3, 10, 13, 30, 0, 0, time.UTC); !since.Equal(expected) {
+		t.Errorf("expected since %v, got %v", expected, since)
+	}
+	if expected := time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC); !until.Equal(expected) {
+		t.Errorf("expected until %v, got %v", expected, until)
+	}
+	if expected := time.Date(2024, 2, 1, 8, 0, 0, 0, time.UTC); !dest.Equal(expected) {
+		t.Errorf("expected destination %v, got %v", expected, dest)
+	}
+	if unset != nil {
+		t.Errorf("expected unset timestamp to be nil, got %v", unset)
+	}
+}
+
+func TestTimestampFlagRelative(t *testing.T) {
+	oldNow := timestampNow
+	defer func() { timestampNow = oldNow }()
+	timestampNow = func() time.Time { return time.Date(2024, 3, 10, 15, 30, 0, 0, time.UTC) }
+
+	codec := TimestampFlag{Location: time.UTC}.codec()
+	for input, expected := range map[string]time.Time{
+		"now":                  time.Date(2024, 3, 10, 15, 30, 0, 0, time.UTC),
+		"today":                time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+		"yesterday":            time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC),
+		"tomorrow+6h":          time.Date(2024, 3, 11, 6, 0, 0, 0, time.UTC),
+		"now-1w":               time.Date(2024, 3, 3, 15, 30, 0, 0, time.UTC),
+		"2024-01-02":           time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		"2024-01-02T03:04:05Z": time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	} {
+		ts, err := codec.Parse(input)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", input, err)
+			continue
+		}
+		if !ts.Equal(expected) {
+			t.Errorf("%s: expected %v, got %v", input, expected, ts)
+		}
+	}
+}
+
+func TestFlagBaseCodecInstance(t *testing.T) {
+	codec := TimestampCodec{Layouts: []string{"02/01/2006"}, Location: time.UTC}
+	f := FlagBase[time.Time, TimestampCodec]{Name: "on", Value: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Codec: codec}
+	if got := f.GetValue(); got != "01/02/2024" {
+		t.Errorf("expected default formatted with the instance layout, got %q", got)
+	}
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	if err := f.ApplyWithError(set); err != nil {
+		t.Fatal(err)
+	}
+	if err := set.Parse([]string{"--on", "03/04/2024"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ts := lookupValue[time.Time]("on", set, nil)
+	if expected := time.Date(2024, 4, 3, 0, 0, 0, 0, time.UTC); !ts.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, ts)
+	}
+	if got := set.Lookup("on").Value.String(); got != "03/04/2024" {
+		t.Errorf("expected value formatted with the instance layout, got %q", got)
+	}
+}
+
+func TestTimestampFlagErrors(t *testing.T) {
+	f := TimestampFlag{Name: "since", Layout: "2006-01-02"}
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	set.SetOutput(io.Discard)
+	if err := f.ApplyWithError(set); err != nil {
+		t.Fatal(err)
+	}
+	err := set.Parse([]string{"--since", "last week"})
+	if err == nil || !strings.Contains(err.Error(), "accepted layouts: 2006-01-02") {
+		t.Errorf("expected error naming accepted layouts, got %v", err)
+	}
+
+	t.Setenv("TOOL_SINCE", "soon")
+	f.EnvVar = "TOOL_SINCE"
+	err = f.ApplyWithError(flag.NewFlagSet("test", flag.ContinueOnError))
+	if err == nil || !strings.Contains(err.Error(), "for flag since") {
+		t.Errorf("expected env parse error, got %v", err)
+	}
+}
+
+func TestTimestampFlagHelp(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	f := TimestampFlag{Name: "since", Usage: "start at `TIME`", Layout: "2006-01-02", Location: time.UTC, Value: &ts}
+	expected := "--since TIME\tstart at TIME (default: 2024-01-02)"
+	if got := f.String(); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+	f.Value = nil
+	expected = "--since TIME\tstart at TIME"
+	if got := f.String(); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestNumericSliceFlags(t *testing.T) {
+	t.Setenv("TOOL_IDS", "7, 8")
+	var weights []float64
+	var ports []uint
+	var ids, globalIDs []uint64
+	app := &App{
+		Name: "tool",
+		Flags: []Flag{
+			Uint64SliceFlag{Name: "id", EnvVar: "TOOL_IDS"},
+		},
+		Commands: []Command{
+			{
+				Name: "train",
+				Flags: []Flag{
+					Float64SliceFlag{Name: "weight, w", Value: &Float64Slice{0.5}},
+					UintSliceFlag{Name: "port"},
+				},
+				Action: func(c *Context) error {
+					weights = c.Float64Slice("weight")
+					ports = c.UintSlice("port")
+					ids = c.Uint64Slice("id")
+					globalIDs = c.GlobalUint64Slice("id")
+					return nil
+				},
+			},
+		},
+	}
+	if err := app.Run([]string{"tool", "train", "-w", "0.3", "-w", "0.7", "--port", "80", "--port", "443"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(weights, []float64{0.3, 0.7}) {
+		t.Errorf("expected weights without the default, got %v", weights)
+	}
+	if !reflect.DeepEqual(ports, []uint{80, 443}) {
+		t.Errorf("unexpected ports %v", ports)
+	}
+	if ids != nil || !reflect.DeepEqual(globalIDs, []uint64{7, 8}) {
+		t.Errorf("expected ids only at the global level, got %v and %v", ids, globalIDs)
+	}
+}
+
+func TestNumericSliceFlagErrorsAndHelp(t *testing.T) {
+	t.Setenv("TOOL_PORTS", "80,-1")
+	err := UintSliceFlag{Name: "port", EnvVar: "TOOL_PORTS"}.ApplyWithError(flag.NewFlagSet("test", flag.ContinueOnError))
+	if err == nil || !strings.Contains(err.Error(), "as uint slice value for flag port") {
+		t.Errorf("expected env parse error, got %v", err)
+	}
+
+	f := Float64SliceFlag{Name: "weight", Usage: "sample `WEIGHT`", Value: &Float64Slice{0.25, 1}}
+	expected := "--weight WEIGHT\tsample WEIGHT (default: 0.25, 1)"
+	if got := f.String(); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+type levelCodec struct{}
+
+func (levelCodec) Parse(value string) (int, error) {
+	for i, name := range []string{"debug", "info", "warn"} {
+		if value == name {
+			return i, nil
+		}
+	}
+	return 0, errors.New("unknown level")
+}
+
+func (levelCodec) Format(value int) string {
+	return []string{"debug", "info", "warn"}[value]
+}
+
+type severityCodec struct {
+	levelCodec
+}
+
+func (severityCodec) FlagTypeName() string {
+	return "SeverityFlag"
+}
+
+func TestFlagBaseCustomCodec(t *testing.T) {
+	type LevelFlag = FlagBase[int, levelCodec]
+	var level, global int
+	var verbose bool
+	app := &App{
+		Name:  "tool",
+		Flags: []Flag{LevelFlag{Name: "level", Value: 1}, BoolFlag{Name: "verbose"}},
+		Commands: []Command{
+			{
+				Name: "run",
+				Action: func(c *Context) error {
+					global = globalValue(c, "level", levelCodec{}.Parse)
+					return nil
+				},
+			},
+		},
+		Before: func(c *Context) error {
+			level = Value[int](c, "level")
+			verbose = Value[bool](c, "verbose")
+			return nil
+		},
+	}
+	if err := app.Run([]string{"tool", "--level", "warn", "--verbose", "run"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if level != 2 || global != 2 || !verbose {
+		t.Errorf("unexpected level %d, global %d, verbose %v", level, global, verbose)
+	}
+
+	f := LevelFlag{Name: "level", Value: 2}
+	if got := f.GetValue(); got != "warn" {
+		t.Errorf("expected formatted default warn, got %q", got)
+	}
+	if got, expected := f.String(), "--level value\t(default: warn)"; got != expected {
+		t.Errorf("expected help %q, got %q", expected, got)
+	}
+	for _, c := range []struct {
+		flag     Flag
+		expected string
+	}{
+		{Float64Flag{Name: "ratio", Value: 0.5}, "--ratio value\t(default: 0.5)"},
+		{StringFlag{Name: "name", Value: "gopher"}, "--name value\t(default: \"gopher\")"},
+		{StringFlag{Name: "name"}, "--name value\t"},
+		{IntFlag{Name: "count"}, "--count value\t(default: 0)"},
+		{DurationFlag{Name: "wait", Value: time.Hour}, "--wait value\t(default: 1h)"},
+	} {
+		if got := c.flag.String(); got != c.expected {
+			t.Errorf("expected help %q, got %q", c.expected, got)
+		}
+	}
+	for _, c := range []struct {
+		flag     Flag
+		expected string
+	}{
+		{f, "LevelFlag"},
+		{FlagBase[int, severityCodec]{}, "SeverityFlag"},
+		{FlagBase[*Slice[int, levelCodec], SliceCodec[int, levelCodec]]{}, "LevelSliceFlag"},
+		{Float64SliceFlag{}, "Float64SliceFlag"},
+		{DurationFlag{}, "DurationFlag"},
+	} {
+		if got := flagTypeName(c.flag); got != c.expected {
+			t.Errorf("expected type name %q, got %q", c.expected, got)
+		}
+	}
+}
+
+func TestSliceFlagDefaults(t *testing.T) {
+	t.Setenv("TOOL_TAGS", "a, b")
+	defaults := &IntSlice{1, 2}
+	var ids []int
+	var tags []string
+	app := &App{
+		Name: "tool",
+		Flags: []Flag{
+			IntSliceFlag{Name: "id", Value: defaults},
+			StringSliceFlag{Name: "tag", EnvVar: "TOOL_TAGS"},
+		},
+		Action: func(c *Context) error {
+			ids, tags = c.IntSlice("id"), c.StringSlice("tag")
+			return nil
+		},
+	}
+	if err := app.Run([]string{"tool", "--id", "2", "--id", "3", "--tag", "c"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(ids, []int{2, 3}) || !reflect.DeepEqual(tags, []string{"c"}) {
+		t.Errorf("expected command line to replace defaults, got %v and %v", ids, tags)
+	}
+	if !reflect.DeepEqual(defaults.Value(), []int{1, 2}) {
+		t.Errorf("expected default slice to be left alone, got %v", defaults.Value())
+	}
+
+	if err := app.Run([]string{"tool"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(ids, []int{1, 2}) || !reflect.DeepEqual(tags, []string{"a", "b"}) {
+		t.Errorf("expected defaults and env values, got %v and %v", ids, tags)
+	}
+
+	f := StringSliceFlag{Name: "tag", Value: &StringSlice{"x", ""}}
+	if got, expected := f.String(), "--tag value\t(default: \"x\")"; got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestFlagBaseEnvError(t *testing.T) {
+	t.Setenv("TOOL_PORT", "http")
+	err := IntFlag{Name: "port", EnvVar: "TOOL_PORT"}.ApplyWithError(flag.NewFlagSet("test", flag.ContinueOnError))
+	if err == nil || err.Error() != "could not parse http as int value for flag port: parse error" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var timestampNow = time.Now
+
+var defaultTimestampLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+type TimestampFlag struct {
+	Name        string
+	Usage       string
+	EnvVar      string
+	FilePath    string
+	Required    bool
+	Hidden      bool
+	Complete    FlagCompleteFunc
+	Layout      string
+	Layouts     []string
+	Location    *time.Location
+	Value       *time.Time
+	Destination *time.Time
+}
+
+func (f TimestampFlag) String() string {
+	return FlagStringer(f)
+}
+
+func (f TimestampFlag) GetName() string {
+	return f.Name
+}
+
+func (f TimestampFlag) IsRequired() bool {
+	return f.Required
+}
+
+func (f TimestampFlag) TakesValue() bool {
+	return true
+}
+
+func (f TimestampFlag) GetUsage() string {
+	return f.Usage
+}
+
+func (f TimestampFlag) GetValue() string {
+	return f.base().GetValue()
+}
+
+func (f TimestampFlag) defaultText() string {
+	return f.GetValue()
+}
+
+func (f TimestampFlag) Apply(set *flag.FlagSet) {
+	_ = f.ApplyWithError(set)
+}
+
+func (f TimestampFlag) ApplyWithError(set *flag.FlagSet) error {
+	return f.base().ApplyWithError(set)
+}
+
+func (f TimestampFlag) base() FlagBase[time.Time, TimestampCodec] {
+	b := FlagBase[time.Time, TimestampCodec]{
+		Name:        f.Name,
+		Usage:       f.Usage,
+		EnvVar:      f.EnvVar,
+		FilePath:    f.FilePath,
+		Required:    f.Required,
+		Hidden:      f.Hidden,
+		Complete:    f.Complete,
+		Destination: f.Destination,
+		Codec:       f.codec(),
+	}
+	if f.Value != nil {
+		b.Value = *f.Value
+	}
+	return b
+}
+
+func (f TimestampFlag) codec() TimestampCodec {
+	var layouts []string
+	if f.Layout != "" {
+		layouts = append(layouts, f.Layout)
+	}
+	return TimestampCodec{Layouts: append(layouts, f.Layouts...), Location: f.Location}
+}
+
+type TimestampCodec struct {
+	Layouts  []string
+	Location *time.Location
+}
+
+func (c TimestampCodec) Parse(value string) (time.Time, error) {
+	if ts, ok, err := c.parseRelative(value); ok {
+		return ts, err
+	}
+	layouts := c.layouts()
+	for _, layout := range layouts {
+		if ts, err := time.ParseInLocation(layout, value, c.location()); err == nil {
+			return ts, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q as a timestamp, accepted layouts: %s (or now, today, yesterday, tomorrow with an optional +/- duration)",
+		value, strings.Join(layouts, ", "))
+}
+
+func (c TimestampCodec) Format(value time.Time) string {
+	if value.IsZero() {
+		return ""
+	}
+	return value.In(c.location()).Format(c.layouts()[0])
+}
+
+func (TimestampCodec) kind() string {
+	return "timestamp"
+}
+
+func (c TimestampCodec) layouts() []string {
+	if len(c.Layouts) == 0 {
+		return defaultTimestampLayouts
+	}
+	return c.Layouts
+}
+
+func (c TimestampCodec) location() *time.Location {
+	if c.Location == nil {
+		return time.Local
+	}
+	return c.Location
+}
+
+func (c TimestampCodec) parseRelative(s string) (time.Time, bool, error) {
+	base, offset := s, ""
+	if i := strings.IndexAny(s, "+-"); i > 0 {
+		base, offset = s[:i], s[i:]
+	}
+	now := timestampNow().In(c.location())
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, c.location())
+	var ts time.Time
+	switch strings.TrimSpace(base) {
+	case "now":
+		ts = now
+	case "today":
+		ts = midnight
+	case "yesterday":
+		ts = midnight.AddDate(0, 0, -1)
+	case "tomorrow":
+		ts = midnight.AddDate(0, 0, 1)
+	default:
+		return time.Time{}, false, nil
+	}
+	if offset == "" {
+		return ts, true, nil
+	}
+	d, err := parseDuration(strings.TrimPrefix(offset, "+"))
+	if err != nil {
+		return time.Time{}, true, fmt.Errorf("invalid offset in %q: %s", s, err)
+	}
+	return ts.Add(d), true, nil
+}
+
+func (c *Context) Timestamp(name string) *time.Time {
+	return timestampOrNil(Value[time.Time](c, name))
+}
+
+func (c *Context) GlobalTimestamp(name string) *time.Time {
+	return timestampOrNil(globalValue[time.Time](c, name, nil))
+}
+
+func timestampOrNil(ts time.Time) *time.Time {
+	if ts.IsZero() {
+		return nil
+	}
+	return &ts
+}
@@ -1,87 +1,31 @@
 package cli
 
-import (
-	"flag"
-	"fmt"
-	"strconv"
-)
+import "strconv"
 
-type UintFlag struct {
-	Name        string
-	Usage       string
-	EnvVar      string
-	FilePath    string
-	Required    bool
-	Hidden      bool
-	Value       uint
-	Destination *uint
-}
-
-func (f UintFlag) String() string {
-	return FlagStringer(f)
-}
-
-func (f UintFlag) GetName() string {
-	return f.Name
-}
-
-func (f UintFlag) IsRequired() bool {
-	return f.Required
-}
-
-func (f UintFlag) TakesValue() bool {
-	return true
-}
+type UintFlag = FlagBase[uint, UintCodec]
 
-func (f UintFlag) GetUsage() string {
-	return f.Usage
-}
+type UintCodec struct{}
 
-func (f UintFlag) Apply(set *flag.FlagSet) {
-	_ = f.ApplyWithError(set)
+func (UintCodec) Parse(value string) (uint, error) {
+	v, err := strconv.ParseUint(value, 0, strconv.IntSize)
+	if err != nil {
+		return 0, numError(err)
+	}
+	return uint(v), nil
 }
 
-func (f UintFlag) ApplyWithError(set *flag.FlagSet) error {
-	if envVal, ok := flagFromFileEnv(f.FilePath, f.EnvVar); ok {
-		envValInt, err := strconv.ParseUint(envVal, 0, 64)
-		if err != nil {
-			return fmt.Errorf("could not parse %s as uint value for flag %s: %s", envVal, f.Name, err)
-		}
-		f.Value = uint(envValInt)
-	}
-	eachName(f.Name, func(name string) {
-		if f.Destination != nil {
-			set.UintVar(f.Destination, name, f.Value, f.Usage)
-			return
-		}
-		set.Uint(name, f.Value, f.Usage)
-	})
-	return nil
+func (UintCodec) Format(value uint) string {
+	return strconv.FormatUint(uint64(value), 10)
 }
 
-func (f UintFlag) GetValue() string {
-	return fmt.Sprintf("%d", f.Value)
+func (UintCodec) kind() string {
+	return "uint"
 }
 
 func (c *Context) Uint(name string) uint {
-	return lookupUint(name, c.flagSet)
+	return lookupValue(name, c.flagSet, UintCodec{}.Parse)
 }
 
 func (c *Context) GlobalUint(name string) uint {
-	if fs := lookupGlobalFlagSet(name, c); fs != nil {
-		return lookupUint(name, fs)
-	}
-	return 0
-}
-
-func lookupUint(name string, set *flag.FlagSet) uint {
-	f := set.Lookup(name)
-	if f != nil {
-		parsed, err := strconv.ParseUint(f.Value.String(), 0, 64)
-		if err != nil {
-			return 0
-		}
-		return uint(parsed)
-	}
-	return 0
+	return globalValue(c, name, UintCodec{}.Parse)
 }
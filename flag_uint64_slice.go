@@ -0,0 +1,13 @@
+package cli
+
+type Uint64Slice = Slice[uint64, Uint64Codec]
+
+type Uint64SliceFlag = FlagBase[*Uint64Slice, SliceCodec[uint64, Uint64Codec]]
+
+func (c *Context) Uint64Slice(name string) []uint64 {
+	return Value[[]uint64](c, name)
+}
+
+func (c *Context) GlobalUint64Slice(name string) []uint64 {
+	return globalValue[[]uint64](c, name, nil)
+}
@@ -0,0 +1,13 @@
+package cli
+
+type UintSlice = Slice[uint, UintCodec]
+
+type UintSliceFlag = FlagBase[*UintSlice, SliceCodec[uint, UintCodec]]
+
+func (c *Context) UintSlice(name string) []uint {
+	return Value[[]uint](c, name)
+}
+
+func (c *Context) GlobalUintSlice(name string) []uint {
+	return globalValue[[]uint](c, name, nil)
+}
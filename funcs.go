@@ -2,6 +2,8 @@ package cli
 
 type BashCompleteFunc func(*Context)
 
+type FlagCompleteFunc func(ctx *Context, partial string) []string
+
 type BeforeFunc func(*Context) error
 
 type AfterFunc func(ctx *Context) error
@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+)
+
+var AppHelpTemplate = `NAME:
+   {{.Name}}{{if .Usage}} - {{.Usage}}{{end}}
+
+USAGE:
+   {{if .UsageText}}{{.UsageText}}{{else}}{{.Name}}{{if .VisibleFlags}} [global options]{{end}}{{if .VisibleCommands}} command [command options]{{end}} {{if .ArgsUsage}}{{.ArgsUsage}}{{else}}[arguments...]{{end}}{{end}}{{if .Version}}{{if not .HideVersion}}
+
+VERSION:
+   {{.Version}}{{end}}{{end}}{{if .Description}}
+
+DESCRIPTION:
+   {{.Description}}{{end}}{{if .VisibleCommands}}
+
+COMMANDS:{{range .VisibleCategories}}{{if .Name}}
+
+   {{.Name}}:{{range .VisibleCommands}}
+     {{join .Names ", "}}{{"\t"}}{{.Usage}}{{end}}{{else}}{{range .VisibleCommands}}
+   {{join .Names ", "}}{{"\t"}}{{.Usage}}{{end}}{{end}}{{end}}{{end}}{{if .VisibleFlags}}
+
+GLOBAL OPTIONS:
+   {{range $index, $option := .VisibleFlags}}{{if $index}}
+   {{end}}{{$option}}{{end}}{{end}}
+`
+
+var CommandHelpTemplate = `NAME:
+   {{.HelpName}}{{if .Usage}} - {{.Usage}}{{end}}
+
+USAGE:
+   {{if .UsageText}}{{.UsageText}}{{else}}{{.HelpName}}{{if .VisibleFlags}} [command options]{{end}} {{if .ArgsUsage}}{{.ArgsUsage}}{{else}}[arguments...]{{end}}{{end}}{{if .Category}}
+
+CATEGORY:
+   {{.Category}}{{end}}{{if .Description}}
+
+DESCRIPTION:
+   {{.Description}}{{end}}{{if .VisibleFlags}}
+
+OPTIONS:
+   {{range $index, $option := .VisibleFlags}}{{if $index}}
+   {{end}}{{$option}}{{end}}{{end}}
+`
+
+var SubcommandHelpTemplate = `NAME:
+   {{.HelpName}}{{if .Usage}} - {{.Usage}}{{end}}
+
+USAGE:
+   {{if .UsageText}}{{.UsageText}}{{else}}{{.HelpName}} command{{if .VisibleFlags}} [command options]{{end}} {{if .ArgsUsage}}{{.ArgsUsage}}{{else}}[arguments...]{{end}}{{end}}{{if .Description}}
+
+DESCRIPTION:
+   {{.Description}}{{end}}
+
+COMMANDS:{{range .VisibleCategories}}{{if .Name}}
+
+   {{.Name}}:{{range .VisibleCommands}}
+     {{join .Names ", "}}{{"\t"}}{{.Usage}}{{end}}{{else}}{{range .VisibleCommands}}
+   {{join .Names ", "}}{{"\t"}}{{.Usage}}{{end}}{{end}}{{end}}{{if .VisibleFlags}}
+
+OPTIONS:
+   {{range $index, $option := .VisibleFlags}}{{if $index}}
+   {{end}}{{$option}}{{end}}{{end}}
+`
+
+var HelpPrinter = printHelp
+
+var helpCommand = Command{
+	Name:      "help",
+	Aliases:   []string{"h"},
+	Usage:     "Shows a list of commands or help for one command",
+	ArgsUsage: "[command]",
+}
+
+func init() {
+	helpCommand.Action = helpAction
+}
+
+func helpAction(c *Context) error {
+	parent := c.parentContext
+	commands := parent.App.Commands
+	if parent.Command.Name != "" {
+		commands = parent.Command.Subcommands
+	}
+	helpName := helpNameFor(parent)
+	var command *Command
+	for _, name := range c.Args().Slice() {
+		found := commands.Command(name)
+		if found == nil {
+			return NewExitError(fmt.Sprintf("No help topic for '%s'", name), 3)
+		}
+		cmd := found.withHelp()
+		command = &cmd
+		command.HelpName = helpName + " " + command.Name
+		helpName = command.HelpName
+		commands = command.Subcommands
+	}
+	if command == nil {
+		return showHelp(parent)
+	}
+	return showCommandHelp(parent.App.Writer, *command)
+}
+
+func ShowAppHelp(c *Context) error {
+	return HelpPrinter(c.App.Writer, AppHelpTemplate, c.App)
+}
+
+func ShowCommandHelp(ctx *Context, command string) error {
+	commands := ctx.App.Commands
+	if ctx.Command.Name != "" {
+		commands = ctx.Command.Subcommands
+	}
+	c := commands.Command(command)
+	if c == nil {
+		return NewExitError(fmt.Sprintf("No help topic for '%s'", command), 3)
+	}
+	cmd := c.withHelp()
+	cmd.HelpName = helpNameFor(ctx) + " " + cmd.Name
+	return showCommandHelp(ctx.App.Writer, cmd)
+}
+
+func showHelp(ctx *Context) error {
+	if ctx.Command.Name == "" {
+		return ShowAppHelp(ctx)
+	}
+	return showCommandHelp(ctx.App.Writer, ctx.Command)
+}
+
+func showCommandHelp(w io.Writer, c Command) error {
+	if len(c.Subcommands) > 0 {
+		return HelpPrinter(w, SubcommandHelpTemplate, c)
+	}
+	return HelpPrinter(w, CommandHelpTemplate, c)
+}
+
+func helpNameFor(ctx *Context) string {
+	if ctx.Command.HelpName != "" {
+		return ctx.Command.HelpName
+	}
+	return ctx.App.Name
+}
+
+func checkHelp(c *Context) bool {
+	found := false
+	eachName(HelpFlag.GetName(), func(name string) {
+		if c.Bool(name) {
+			found = true
+		}
+	})
+	return found
+}
+
+func helpRequested(c *Context, commands Commands) bool {
+	arguments := c.Args().Slice()
+	for i, arg := range arguments {
+		if arg == "--" {
+			break
+		}
+		found := false
+		eachName(HelpFlag.GetName(), func(name string) {
+			if arg == "-"+name || arg == "--"+name {
+				found = true
+			}
+		})
+		if found {
+			return true
+		}
+		if command := commands.Command(arg); command != nil {
+			if command.Name == helpCommand.Name || leafHelpRequested(*command, arguments[i+1:]) {
+				return true
+			}
+			commands = command.withHelp().Subcommands
+		}
+	}
+	return false
+}
+
+func leafHelpRequested(c Command, arguments []string) bool {
+	if c.HideHelp || c.SkipFlagParsing || len(c.Subcommands) > 0 {
+		return false
+	}
+	return len(arguments) == 1 && arguments[0] == helpCommand.Name
+}
+
+func printHelp(out io.Writer, templ string, data interface{}) error {
+	funcMap := template.FuncMap{
+		"join": strings.Join,
+	}
+	w := tabwriter.NewWriter(out, 1, 8, 2, ' ', 0)
+	t, err := template.New("help").Funcs(funcMap).Parse(templ)
+	if err != nil {
+		return err
+	}
+	if err := t.Execute(w, data); err != nil {
+		return err
+	}
+	return w.Flush()
+}
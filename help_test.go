@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newHelpTestApp(out *bytes.Buffer) *App {
+	return &App{
+		Name:   "tool",
+		Usage:  "does things",
+		Writer: out,
+		Flags:  []Flag{BoolFlag{Name: "verbose", Usage: "be loud"}},
+		Commands: []Command{
+			{Name: "status", Usage: "show status", Action: func(c *Context) error { return nil }},
+			{Name: "secret", Hidden: true},
+			{
+				Name:     "remote",
+				Usage:    "manage remotes",
+				Category: "setup",
+				Subcommands: []Command{
+					{
+						Name:      "add",
+						Usage:     "add a remote",
+						ArgsUsage: "NAME URL",
+						Flags:     []Flag{IntFlag{Name: "depth", Value: 1, Usage: "clone depth"}},
+						Action:    func(c *Context) error { return nil },
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestShowAppHelp(t *testing.T) {
+	var out bytes.Buffer
+	if err := newHelpTestApp(&out).Run([]string{"tool", "--help"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	help := out.String()
+	for _, expected := range []string{
+		"tool - does things",
+		"   status   show status\n",
+		"   setup:\n     remote  manage remotes",
+		"   --verbose      be loud\n",
+	} {
+		if !strings.Contains(help, expected) {
+			t.Errorf("expected help to contain %q, got:\n%s", expected, help)
+		}
+	}
+	if strings.Contains(help, "secret") {
+		t.Errorf("expected hidden command to be omitted, got:\n%s", help)
+	}
+}
+
+func TestShowCommandHelp(t *testing.T) {
+	for _, args := range [][]string{
+		{"tool", "help", "remote", "add"},
+		{"tool", "remote", "help", "add"},
+		{"tool", "remote", "add", "-h"},
+		{"tool", "remote", "add", "help"},
+	} {
+		var out bytes.Buffer
+		if err := newHelpTestApp(&out).Run(args); err != nil {
+			t.Fatalf("%v: unexpected error: %v", args, err)
+		}
+		help := out.String()
+		for _, expected := range []string{
+			"tool remote add - add a remote",
+			"tool remote add [command options] NAME URL",
+			"--depth value  clone depth (default: 1)",
+		} {
+			if !strings.Contains(help, expected) {
+				t.Errorf("%v: expected help to contain %q, got:\n%s", args, expected, help)
+			}
+		}
+	}
+}
+
+func TestLeafCommandHelp(t *testing.T) {
+	var out bytes.Buffer
+	var got []string
+	app := newHelpTestApp(&out)
+	app.Commands[0].Action = func(c *Context) error {
+		got = c.Args().Slice()
+		return nil
+	}
+	if err := app.Run([]string{"tool", "status", "help"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != nil {
+		t.Errorf("expected status action not to run, got args %v", got)
+	}
+	if help := out.String(); !strings.Contains(help, "tool status - show status") {
+		t.Errorf("expected status help, got:\n%s", help)
+	}
+
+	out.Reset()
+	if err := app.Run([]string{"tool", "status", "help", "me"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"help", "me"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected status action to get %v, got %v", expected, got)
+	}
+}
+
+func TestHelpWithRequiredFlags(t *testing.T) {
+	for _, args := range [][]string{
+		{"tool", "help", "status"},
+		{"tool", "status", "--help"},
+		{"tool", "remote", "add", "-h"},
+		{"tool", "remote", "help", "add"},
+		{"tool", "status", "help"},
+		{"tool", "remote", "add", "help"},
+	} {
+		var out bytes.Buffer
+		app := newHelpTestApp(&out)
+		app.Flags = append(app.Flags, StringFlag{Name: "token", Required: true})
+		app.Commands[2].Flags = []Flag{StringFlag{Name: "origin", Required: true}}
+		if err := app.Run(args); err != nil {
+			t.Fatalf("%v: unexpected error: %v", args, err)
+		}
+		if !strings.Contains(out.String(), "USAGE:") {
+			t.Errorf("%v: expected help output, got:\n%s", args, out.String())
+		}
+	}
+
+	app := newHelpTestApp(&bytes.Buffer{})
+	app.Flags = append(app.Flags, StringFlag{Name: "token", Required: true})
+	app.ExitErrHandler = func(c *Context, err error) {}
+	if err := app.Run([]string{"tool", "status", "--", "--help"}); err == nil {
+		t.Error("expected required flag error when --help follows --")
+	}
+}
+
+func TestShowAppHelpTemplateError(t *testing.T) {
+	oldTemplate := AppHelpTemplate
+	defer func() { AppHelpTemplate = oldTemplate }()
+
+	AppHelpTemplate = "{{.Missing}}"
+	if err := newHelpTestApp(&bytes.Buffer{}).Run([]string{"tool", "--help"}); err == nil {
+		t.Error("expected an error from executing the template")
+	}
+	AppHelpTemplate = "{{.Name"
+	if err := newHelpTestApp(&bytes.Buffer{}).Run([]string{"tool", "--help"}); err == nil {
+		t.Error("expected an error from parsing the template")
+	}
+}
@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+var roffEscaper = strings.NewReplacer(`\`, `\e`, `-`, `\-`)
+
+func roffEscape(s string) string {
+	lines := strings.Split(roffEscaper.Replace(s), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (a *App) ToMan() (string, error) {
+	a.Setup()
+	var w strings.Builder
+	fmt.Fprintf(&w, ".TH %q 1 \"\" %q \"User Commands\"\n", strings.ToUpper(a.Name), strings.TrimSpace(a.Name+" "+a.Version))
+
+	w.WriteString(".SH NAME\n")
+	if a.Usage != "" {
+		fmt.Fprintf(&w, "%s \\- %s\n", roffEscape(a.Name), roffEscape(a.Usage))
+	} else {
+		fmt.Fprintf(&w, "%s\n", roffEscape(a.Name))
+	}
+
+	w.WriteString(".SH SYNOPSIS\n")
+	if a.UsageText != "" {
+		fmt.Fprintf(&w, "%s\n", roffEscape(a.UsageText))
+	} else {
+		fmt.Fprintf(&w, ".B %s\n", roffEscape(a.Name))
+		if len(a.VisibleFlags()) > 0 {
+			w.WriteString("[\\fIglobal options\\fR]\n")
+		}
+		if len(a.VisibleCommands()) > 0 {
+			w.WriteString("\\fIcommand\\fR [\\fIcommand options\\fR]\n")
+		}
+		argsUsage := a.ArgsUsage
+		if argsUsage == "" {
+			argsUsage = "[arguments...]"
+		}
+		fmt.Fprintf(&w, "\\fI%s\\fR\n", roffEscape(argsUsage))
+	}
+
+	if a.Description != "" {
+		fmt.Fprintf(&w, ".SH DESCRIPTION\n%s\n", roffEscape(a.Description))
+	}
+
+	if flags := a.VisibleFlags(); len(flags) > 0 {
+		w.WriteString(".SH \"GLOBAL OPTIONS\"\n")
+		writeManFlags(&w, flags)
+	}
+
+	if commands := a.VisibleCommands(); len(commands) > 0 {
+		w.WriteString(".SH COMMANDS\n")
+		writeManCommands(&w, "", commands)
+	}
+
+	var env, files []string
+	var envDocs, fileDocs []flagDoc
+	collectManSources(a.VisibleFlags(), a.Commands, func(doc flagDoc) {
+		for _, name := range doc.EnvVars {
+			if !containsString(env, name) {
+				env = append(env, name)
+				envDocs = append(envDocs, doc)
+			}
+		}
+		for _, path := range doc.FilePaths {
+			if !containsString(files, path) {
+				files = append(files, path)
+				fileDocs = append(fileDocs, doc)
+			}
+		}
+	})
+	if len(env) > 0 {
+		w.WriteString(".SH ENVIRONMENT\n")
+		for i, name := range env {
+			fmt.Fprintf(&w, ".TP\n.B %s\n%s\n", roffEscape(name), manSourceUsage(envDocs[i]))
+		}
+	}
+	if len(files) > 0 {
+		w.WriteString(".SH FILES\n")
+		for i, path := range files {
+			fmt.Fprintf(&w, ".TP\n.I %s\n%s\n", roffEscape(path), manSourceUsage(fileDocs[i]))
+		}
+	}
+	return w.String(), nil
+}
+
+func writeManCommands(w *strings.Builder, path string, commands []Command) {
+	for _, command := range commands {
+		commandPath := strings.TrimSpace(path + " " + command.Name)
+		fmt.Fprintf(w, ".SS \"%s\"\n", roffEscape(commandPath))
+		if len(command.Aliases) > 0 {
+			fmt.Fprintf(w, "Aliases: %s\n.PP\n", roffEscape(strings.Join(command.Aliases, ", ")))
+		}
+		if command.Usage != "" {
+			fmt.Fprintf(w, "%s\n", roffEscape(command.Usage))
+		}
+		if command.Description != "" {
+			fmt.Fprintf(w, ".PP\n%s\n", roffEscape(command.Description))
+		}
+		if flags := command.VisibleFlags(); len(flags) > 0 {
+			w.WriteString(".PP\n.B Options:\n.RS\n")
+			writeManFlags(w, flags)
+			w.WriteString(".RE\n")
+		}
+		writeManCommands(w, commandPath, command.VisibleCommands())
+	}
+}
+
+func writeManFlags(w *strings.Builder, flags []Flag) {
+	for _, f := range flags {
+		doc := newFlagDoc(f)
+		names := make([]string, len(doc.Names))
+		for i, name := range doc.prefixedNames() {
+			names[i] = "\\fB" + roffEscape(name) + "\\fR"
+		}
+		synopsis := strings.Join(names, ", ")
+		if doc.TakesValue {
+			synopsis += " \\fI" + roffEscape(doc.Placeholder) + "\\fR"
+		}
+		fmt.Fprintf(w, ".TP\n%s\n", synopsis)
+		usage := doc.Usage
+		if doc.Default != "" {
+			usage = strings.TrimSpace(fmt.Sprintf("%s (default: %s)", usage, doc.Default))
+		}
+		if doc.Required {
+			usage = strings.TrimSpace(usage + " (required)")
+		}
+		fmt.Fprintf(w, "%s\n", roffEscape(usage))
+	}
+}
+
+func collectManSources(flags []Flag, commands Commands, fn func(flagDoc)) {
+	for _, f := range flags {
+		fn(newFlagDoc(f))
+	}
+	for _, command := range visibleCommands(commands) {
+		collectManSources(command.VisibleFlags(), command.Subcommands, fn)
+	}
+}
+
+func manSourceUsage(doc flagDoc) string {
+	usage := "Sets " + strings.Join(doc.prefixedNames(), ", ")
+	if doc.Usage != "" {
+		usage += ": " + doc.Usage
+	}
+	return roffEscape(usage)
+}
@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type markdownPage struct {
+	Path        []string
+	Aliases     []string
+	Usage       string
+	UsageText   string
+	Description string
+	Flags       []Flag
+	Commands    []Command
+	Parent      *markdownPage
+}
+
+func (p *markdownPage) name() string {
+	return strings.Join(p.Path, " ")
+}
+
+func (a *App) markdownPages() []*markdownPage {
+	a.Setup()
+	root := &markdownPage{
+		Path:        []string{a.Name},
+		Usage:       a.Usage,
+		UsageText:   a.UsageText,
+		Description: a.Description,
+		Flags:       a.VisibleFlags(),
+		Commands:    a.VisibleCommands(),
+	}
+	root.UsageText = markdownUsageText(root, a.ArgsUsage, "global options")
+	if a.UsageText != "" {
+		root.UsageText = a.UsageText
+	}
+	return append([]*markdownPage{root}, markdownCommandPages(root)...)
+}
+
+func markdownCommandPages(parent *markdownPage) []*markdownPage {
+	var pages []*markdownPage
+	for _, command := range parent.Commands {
+		page := &markdownPage{
+			Path:        append(parent.Path[:len(parent.Path):len(parent.Path)], command.Name),
+			Aliases:     command.Aliases,
+			Usage:       command.Usage,
+			Description: command.Description,
+			Flags:       command.VisibleFlags(),
+			Commands:    command.VisibleCommands(),
+			Parent:      parent,
+		}
+		page.UsageText = markdownUsageText(page, command.ArgsUsage, "command options")
+		if command.UsageText != "" {
+			page.UsageText = command.UsageText
+		}
+		pages = append(pages, page)
+		pages = append(pages, markdownCommandPages(page)...)
+	}
+	return pages
+}
+
+func markdownUsageText(page *markdownPage, argsUsage, optionsName string) string {
+	usage := page.name()
+	if len(page.Flags) > 0 {
+		usage += " [" + optionsName + "]"
+	}
+	if len(page.Commands) > 0 {
+		usage += " command [command options]"
+	}
+	if argsUsage == "" {
+		argsUsage = "[arguments...]"
+	}
+	return usage + " " + argsUsage
+}
+
+func (a *App) ToMarkdown() (string, error) {
+	var w strings.Builder
+	for _, page := range a.markdownPages() {
+		level := "##"
+		if page.Parent == nil {
+			level = "#"
+		}
+		writeMarkdownPage(&w, page, level, markdownAnchor)
+	}
+	return strings.TrimRight(w.String(), "\n") + "\n", nil
+}
+
+func (a *App) ToMarkdownFiles(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, page := range a.markdownPages() {
+		var w strings.Builder
+		writeMarkdownPage(&w, page, "#", markdownFileName)
+		if err := os.WriteFile(filepath.Join(dir, markdownFileName(page.Path)), []byte(strings.TrimRight(w.String(), "\n")+"\n"), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeMarkdownPage(w *strings.Builder, page *markdownPage, level string, link func([]string) string) {
+	fmt.Fprintf(w, "%s %s\n\n", level, page.name())
+	if page.Usage != "" {
+		fmt.Fprintf(w, "%s\n\n", page.Usage)
+	}
+	if page.Parent != nil {
+		fmt.Fprintf(w, "Parent: [%s](%s)\n\n", page.Parent.name(), link(page.Parent.Path))
+	}
+	fmt.Fprintf(w, "**Usage:**\n\n```\n%s\n```\n\n", page.UsageText)
+	if page.Description != "" {
+		fmt.Fprintf(w, "%s\n\n", page.Description)
+	}
+	if len(page.Aliases) > 0 {
+		fmt.Fprintf(w, "**Aliases:** %s\n\n", "`"+strings.Join(page.Aliases, "`, `")+"`")
+	}
+	if len(page.Commands) > 0 {
+		w.WriteString("**Commands:**\n\n| Command | Aliases | Description |\n| --- | --- | --- |\n")
+		for _, command := range page.Commands {
+			path := append(page.Path[:len(page.Path):len(page.Path)], command.Name)
+			fmt.Fprintf(w, "| [%s](%s) | %s | %s |\n",
+				command.Name, link(path), markdownCode(command.Aliases), markdownCell(command.Usage))
+		}
+		w.WriteString("\n")
+	}
+	if len(page.Flags) > 0 {
+		if page.Parent == nil {
+			w.WriteString("**Global options:**\n\n")
+		} else {
+			w.WriteString("**Options:**\n\n")
+		}
+		w.WriteString("| Flag | Value | Default | Environment | Files | Required | Description |\n")
+		w.WriteString("| --- | --- | --- | --- | --- | --- | --- |\n")
+		for _, f := range page.Flags {
+			doc := newFlagDoc(f)
+			var placeholder, defaultValue []string
+			if doc.TakesValue {
+				placeholder = []string{doc.Placeholder}
+			}
+			if doc.Default != "" {
+				defaultValue = []string{doc.Default}
+			}
+			required := "no"
+			if doc.Required {
+				required = "yes"
+			}
+			fmt.Fprintf(w, "| %s | %s | %s | %s | %s | %s | %s |\n",
+				markdownCode(doc.prefixedNames()), markdownCode(placeholder), markdownCode(defaultValue),
+				markdownCode(doc.EnvVars), markdownCode(doc.FilePaths), required, markdownCell(doc.Usage))
+		}
+		w.WriteString("\n")
+	}
+}
+
+func markdownCode(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = "`" + markdownCell(v) + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+var markdownCellEscaper = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>")
+
+func markdownCell(s string) string {
+	return markdownCellEscaper.Replace(s)
+}
+
+var markdownAnchorStrip = regexp.MustCompile(`[^a-z0-9 _-]`)
+
+func markdownAnchor(path []string) string {
+	anchor := markdownAnchorStrip.ReplaceAllString(strings.ToLower(strings.Join(path, " ")), "")
+	return "#" + strings.Replace(anchor, " ", "-", -1)
+}
+
+func markdownFileName(path []string) string {
+	return nonIdentifierChars.ReplaceAllString(strings.Join(path, "_"), "_") + ".md"
+}
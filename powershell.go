@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+var powerShellCompletionHeader = `# %[1]s PowerShell completion
+
+using namespace System.Management.Automation
+using namespace System.Management.Automation.Language
+
+Register-ArgumentCompleter -Native -CommandName '%[1]s' -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+
+    $commandElements = $commandAst.CommandElements
+    $command = @(
+        '%[1]s'
+        for ($i = 1; $i -lt $commandElements.Count; $i++) {
+            $element = $commandElements[$i]
+            if ($element -isnot [StringConstantExpressionAst] -or
+                $element.StringConstantType -ne [StringConstantType]::BareWord -or
+                $element.Value -eq $wordToComplete) {
+                break
+            }
+            if ($element.Value.StartsWith('-')) {
+                continue
+            }
+            $element.Value
+        }) -join ';'
+
+    $completions = @(switch ($command) {
+`
+
+var powerShellCompletionFooter = `    })
+
+    $completions.Where{ $_.CompletionText -like "$wordToComplete*" } |
+        Sort-Object -Property ListItemText
+}
+`
+
+func (a *App) ToPowerShellCompletion() (string, error) {
+	a.Setup()
+	var w strings.Builder
+	fmt.Fprintf(&w, powerShellCompletionHeader, powerShellEscape(a.Name))
+	writePowerShellCommand(&w, []string{a.Name}, a.VisibleFlags(), a.Commands)
+	w.WriteString(powerShellCompletionFooter)
+	return w.String(), nil
+}
+
+func writePowerShellCommand(w *strings.Builder, paths []string, flags []Flag, commands Commands) {
+	visible := visibleCommands(commands)
+	quoted := make([]string, len(paths))
+	for i, path := range paths {
+		quoted[i] = "'" + powerShellEscape(path) + "'"
+	}
+	if len(quoted) == 1 {
+		fmt.Fprintf(w, "        %s {\n", quoted[0])
+	} else {
+		fmt.Fprintf(w, "        { $_ -in @(%s) } {\n", strings.Join(quoted, ", "))
+	}
+	for _, f := range flags {
+		var usage string
+		if df, ok := f.(DocGenerationFlag); ok {
+			_, usage = unquoteUsage(df.GetUsage())
+		}
+		eachName(f.GetName(), func(name string) {
+			writePowerShellResult(w, prefixFor(name)+name, "ParameterName", usage)
+		})
+	}
+	for _, command := range visible {
+		for _, name := range command.Names() {
+			writePowerShellResult(w, name, "ParameterValue", command.Usage)
+		}
+	}
+	w.WriteString("            break\n        }\n")
+	for _, command := range visible {
+		command = command.withHelp()
+		var subPaths []string
+		for _, path := range paths {
+			for _, name := range command.Names() {
+				subPaths = append(subPaths, path+";"+name)
+			}
+		}
+		writePowerShellCommand(w, subPaths, command.VisibleFlags(), command.Subcommands)
+	}
+}
+
+func writePowerShellResult(w *strings.Builder, text, resultType, toolTip string) {
+	if toolTip == "" {
+		toolTip = text
+	}
+	fmt.Fprintf(w, "            [CompletionResult]::new('%s', '%s', [CompletionResultType]::%s, '%s')\n",
+		powerShellEscape(text), powerShellEscape(text), resultType, powerShellEscape(toolTip))
+}
+
+func powerShellEscape(s string) string {
+	return strings.Replace(s, "'", "''", -1)
+}
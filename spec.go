@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type Spec struct {
+	Name     string        `json:"name"`
+	Version  string        `json:"version,omitempty"`
+	Usage    string        `json:"usage,omitempty"`
+	Flags    []FlagSpec    `json:"flags"`
+	Commands []CommandSpec `json:"commands"`
+}
+
+type CommandSpec struct {
+	Path       string     `json:"path"`
+	Name       string     `json:"name"`
+	Aliases    []string   `json:"aliases,omitempty"`
+	Category   string     `json:"category,omitempty"`
+	Usage      string     `json:"usage,omitempty"`
+	Hidden     bool       `json:"hidden,omitempty"`
+	Deprecated string     `json:"deprecated,omitempty"`
+	Flags      []FlagSpec `json:"flags"`
+}
+
+type FlagSpec struct {
+	Name       string   `json:"name"`
+	Names      []string `json:"names"`
+	Type       string   `json:"type"`
+	TakesValue bool     `json:"takes_value"`
+	Default    string   `json:"default,omitempty"`
+	EnvVars    []string `json:"env_vars,omitempty"`
+	FilePaths  []string `json:"file_paths,omitempty"`
+	Required   bool     `json:"required,omitempty"`
+	Hidden     bool     `json:"hidden,omitempty"`
+	Usage      string   `json:"usage,omitempty"`
+}
+
+func (a *App) Spec() Spec {
+	a.Setup()
+	spec := Spec{
+		Name:     a.Name,
+		Version:  a.Version,
+		Usage:    a.Usage,
+		Flags:    flagSpecs(a.Flags),
+		Commands: []CommandSpec{},
+	}
+	spec.Commands = appendCommandSpecs(spec.Commands, "", a.Commands)
+	return spec
+}
+
+func appendCommandSpecs(specs []CommandSpec, parent string, commands Commands) []CommandSpec {
+	for _, command := range commands {
+		path := strings.TrimSpace(parent + " " + command.Name)
+		specs = append(specs, CommandSpec{
+			Path:       path,
+			Name:       command.Name,
+			Aliases:    command.Aliases,
+			Category:   command.Category,
+			Usage:      command.Usage,
+			Hidden:     command.Hidden,
+			Deprecated: command.Deprecated,
+			Flags:      flagSpecs(command.Flags),
+		})
+		specs = appendCommandSpecs(specs, path, command.Subcommands)
+	}
+	return specs
+}
+
+func flagSpecs(flags []Flag) []FlagSpec {
+	specs := []FlagSpec{}
+	for _, f := range flags {
+		doc := newFlagDoc(f)
+		spec := FlagSpec{
+			Names:      doc.Names,
+			Type:       flagTypeName(f),
+			TakesValue: doc.TakesValue,
+			Default:    doc.Default,
+			EnvVars:    doc.EnvVars,
+			FilePaths:  doc.FilePaths,
+			Required:   doc.Required,
+			Hidden:     flagHidden(f),
+			Usage:      doc.Usage,
+		}
+		if len(doc.Names) > 0 {
+			spec.Name = doc.Names[0]
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+func printSpec(c *Context) error {
+	data, err := json.MarshalIndent(c.App.Spec(), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(c.App.Writer, string(data))
+	return err
+}
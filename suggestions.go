@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var SuggestionsMinimumDistance = 2
+
+func SuggestCommands(commands Commands, name string) []string {
+	distances := map[string]int{}
+	for _, command := range commands {
+		if command.Hidden {
+			continue
+		}
+		for _, candidate := range command.Names() {
+			distance := levenshteinDistance(strings.ToLower(name), strings.ToLower(candidate))
+			if distance > SuggestionsMinimumDistance && !strings.HasPrefix(strings.ToLower(candidate), strings.ToLower(name)) {
+				continue
+			}
+			if d, ok := distances[candidate]; !ok || distance < d {
+				distances[candidate] = distance
+			}
+		}
+	}
+	suggestions := make([]string, 0, len(distances))
+	for candidate := range distances {
+		suggestions = append(suggestions, candidate)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		di, dj := distances[suggestions[i]], distances[suggestions[j]]
+		if di != dj {
+			return di < dj
+		}
+		return lexicographicLess(suggestions[i], suggestions[j])
+	})
+	return suggestions
+}
+
+func levenshteinDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	prev := make([]int, len(t)+1)
+	curr := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		curr[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(t)]
+}
+
+func commandNotFoundMessage(parent, name string, suggestions []string) string {
+	msg := fmt.Sprintf("'%s' is not a %s command.", name, parent)
+	if len(suggestions) == 0 {
+		return msg
+	}
+	quoted := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		quoted[i] = "'" + s + "'"
+	}
+	return fmt.Sprintf("%s did you mean %s?", msg, strings.Join(quoted, " or "))
+}
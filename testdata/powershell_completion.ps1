@@ -0,0 +1,79 @@
+# tool PowerShell completion
+
+using namespace System.Management.Automation
+using namespace System.Management.Automation.Language
+
+Register-ArgumentCompleter -Native -CommandName 'tool' -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+
+    $commandElements = $commandAst.CommandElements
+    $command = @(
+        'tool'
+        for ($i = 1; $i -lt $commandElements.Count; $i++) {
+            $element = $commandElements[$i]
+            if ($element -isnot [StringConstantExpressionAst] -or
+                $element.StringConstantType -ne [StringConstantType]::BareWord -or
+                $element.Value -eq $wordToComplete) {
+                break
+            }
+            if ($element.Value.StartsWith('-')) {
+                continue
+            }
+            $element.Value
+        }) -join ';'
+
+    $completions = @(switch ($command) {
+        'tool' {
+            [CompletionResult]::new('--verbose', '--verbose', [CompletionResultType]::ParameterName, '--verbose')
+            [CompletionResult]::new('-V', '-V', [CompletionResultType]::ParameterName, '-V')
+            [CompletionResult]::new('--help', '--help', [CompletionResultType]::ParameterName, 'show help')
+            [CompletionResult]::new('-h', '-h', [CompletionResultType]::ParameterName, 'show help')
+            [CompletionResult]::new('status', 'status', [CompletionResultType]::ParameterValue, 'show the working tree''s status')
+            [CompletionResult]::new('st', 'st', [CompletionResultType]::ParameterValue, 'show the working tree''s status')
+            [CompletionResult]::new('remote', 'remote', [CompletionResultType]::ParameterValue, 'remote')
+            [CompletionResult]::new('rm', 'rm', [CompletionResultType]::ParameterValue, 'rm')
+            [CompletionResult]::new('help', 'help', [CompletionResultType]::ParameterValue, 'Shows a list of commands or help for one command')
+            [CompletionResult]::new('h', 'h', [CompletionResultType]::ParameterValue, 'Shows a list of commands or help for one command')
+            break
+        }
+        { $_ -in @('tool;status', 'tool;st') } {
+            [CompletionResult]::new('--help', '--help', [CompletionResultType]::ParameterName, 'show help')
+            [CompletionResult]::new('-h', '-h', [CompletionResultType]::ParameterName, 'show help')
+            break
+        }
+        { $_ -in @('tool;remote', 'tool;rm') } {
+            [CompletionResult]::new('--help', '--help', [CompletionResultType]::ParameterName, 'show help')
+            [CompletionResult]::new('-h', '-h', [CompletionResultType]::ParameterName, 'show help')
+            [CompletionResult]::new('add', 'add', [CompletionResultType]::ParameterValue, 'add')
+            [CompletionResult]::new('remove', 'remove', [CompletionResultType]::ParameterValue, 'remove')
+            [CompletionResult]::new('help', 'help', [CompletionResultType]::ParameterValue, 'Shows a list of commands or help for one command')
+            [CompletionResult]::new('h', 'h', [CompletionResultType]::ParameterValue, 'Shows a list of commands or help for one command')
+            break
+        }
+        { $_ -in @('tool;remote;add', 'tool;rm;add') } {
+            [CompletionResult]::new('--name', '--name', [CompletionResultType]::ParameterName, '--name')
+            [CompletionResult]::new('--depth', '--depth', [CompletionResultType]::ParameterName, '--depth')
+            [CompletionResult]::new('--help', '--help', [CompletionResultType]::ParameterName, 'show help')
+            [CompletionResult]::new('-h', '-h', [CompletionResultType]::ParameterName, 'show help')
+            break
+        }
+        { $_ -in @('tool;remote;remove', 'tool;rm;remove') } {
+            [CompletionResult]::new('--help', '--help', [CompletionResultType]::ParameterName, 'show help')
+            [CompletionResult]::new('-h', '-h', [CompletionResultType]::ParameterName, 'show help')
+            break
+        }
+        { $_ -in @('tool;remote;help', 'tool;remote;h', 'tool;rm;help', 'tool;rm;h') } {
+            [CompletionResult]::new('--help', '--help', [CompletionResultType]::ParameterName, 'show help')
+            [CompletionResult]::new('-h', '-h', [CompletionResultType]::ParameterName, 'show help')
+            break
+        }
+        { $_ -in @('tool;help', 'tool;h') } {
+            [CompletionResult]::new('--help', '--help', [CompletionResultType]::ParameterName, 'show help')
+            [CompletionResult]::new('-h', '-h', [CompletionResultType]::ParameterName, 'show help')
+            break
+        }
+    })
+
+    $completions.Where{ $_.CompletionText -like "$wordToComplete*" } |
+        Sort-Object -Property ListItemText
+}
@@ -0,0 +1,59 @@
+package cli
+
+var zshCompletionTemplate = `#compdef {{.Name}}
+# {{.Name}} zsh completion
+
+_{{.FuncName}}() {
+  local -a lines completions
+  local out directive line value desc
+  out=$("${words[1]}" {{.Command}} "${(@)words[2,$CURRENT]}" 2>/dev/null) || return 1
+  lines=("${(@f)out}")
+  directive=${lines[-1]#:}
+  lines[-1]=()
+
+  if (( directive & {{.Error}} )); then
+    return 1
+  fi
+  if (( directive & {{.FilterFileExt}} )); then
+    _files -g "*.(${(j:|:)lines})"
+    return
+  fi
+  if (( directive & {{.FilterDirs}} )); then
+    _files -/
+    return
+  fi
+
+  for line in "${lines[@]}"; do
+    value=${line%%$'\t'*}
+    value=${value//:/\\:}
+    if [[ $line == *$'\t'* ]]; then
+      desc=${line#*$'\t'}
+      completions+=("${value}:${desc}")
+    else
+      completions+=("${value}")
+    fi
+  done
+
+  if (( ${#completions} )); then
+    if (( directive & {{.NoSpace}} )); then
+      _describe -t values 'completions' completions -S ''
+    else
+      _describe -t values 'completions' completions
+    fi
+    return
+  fi
+  if ! (( directive & {{.NoFileComp}} )); then
+    _files
+  fi
+}
+
+if [ "$funcstack[1]" = "_{{.FuncName}}" ]; then
+  _{{.FuncName}} "$@"
+else
+  compdef _{{.FuncName}} {{.Name}}
+fi
+`
+
+func (a *App) ToZshCompletion() (string, error) {
+	return a.executeCompletionTemplate("zsh", zshCompletionTemplate)
+}